@@ -9,13 +9,18 @@
 //
 // Key Features:
 //   - Generic route state management for type-safe handler contexts
-//   - Built-in worker pool for concurrent request handling
+//   - Built-in worker pool for concurrent request handling, with keep-alive connections
+//     parked outside the pool while idle
 //   - Automatic CORS handling with configurable policies
 //   - Custom JSON parser with field-by-field validation
 //   - Database connection pooling and context management
 //   - Middleware support with early termination capabilities
-//   - Zero-allocation routing with trie-based path matching
+//   - Zero-allocation routing with a compressed radix index over the route trie
 //   - Graceful shutdown with context cancellation support
+//   - net/http.Handler implementation for embedding in standard library servers
+//   - Mounting net/http.Handlers such as pprof or http.FileServer under a path prefix
+//   - Configurable request size limits, overridable per route
+//   - Separate header, body, handler, write and idle timeouts
 //
 // Example usage:
 //
@@ -40,6 +45,7 @@ import (
 	"os/signal"
 	"strings"
 	"sync"
+	"time"
 )
 
 // RouteStateCompatible is a type constraint that allows any type to be used
@@ -58,15 +64,59 @@ type RouteStateCompatible any
 //
 // Fields:
 //   - Port: The port string the server will listen on (e.g., ":8080", "localhost:3000")
-//   - Routes: The route collection managing all registered endpoints and their handlers
+//   - Routes: The route collection managing all registered endpoints and their handlers;
+//     it also serves as the fallback for requests whose Host matches no pattern added with Host
 //   - CorsOrigin: CORS Access-Control-Allow-Origin header value (default: "*")
 //   - CorsHeaders: CORS Access-Control-Allow-Headers header value (default: "*")
-//   - CorsMethods: CORS Access-Control-Allow-Methods header value (default: all common methods)
+//   - CorsMethods: CORS Access-Control-Allow-Methods header value (default: all common methods);
+//     OPTIONS preflight responses list the methods of the matched route instead
 //   - SilentMode: When true, suppresses startup and route registration output
 //   - Database: SQL database connection available to all route handlers
 //   - Context: Application context for graceful shutdown and request cancellation
 //   - WorkerCount: Number of goroutines handling concurrent requests (default: 10)
 //   - LogRequestsLevel: Request logging verbosity (0=none, 1=basic, 2=detailed)
+//   - NotFoundHandler: Optional handler for requests that match no route (default: plain 404)
+//   - MethodNotAllowedHandler: Optional handler for routes without a handler for the request
+//     method (default: plain 405); path parameters of the matched route are available
+//   - BadRequestHandler: Optional handler for requests that could not be parsed (default:
+//     plain 400); only IpAddress and any fields parsed before the error are set
+//   - DecodePath: Percent-decode each path segment before matching; an encoded slash
+//     stays inside its segment, so parameters may contain "/" (default: true)
+//   - CleanPath: Resolve ".", ".." and duplicate slashes before matching (default: true)
+//   - TrailingSlash: How a trailing slash that differs from the registered route is
+//     handled (default: TrailingSlashIgnore)
+//   - VersionHeader: Request header naming the API version for versioned routes
+//     (default: "Accept-Version"); empty disables it
+//   - VersionMediaType: Vendor media type prefix in Accept carrying the version, such as
+//     "application/vnd.acme" for "application/vnd.acme.v2+json" (default: disabled)
+//   - VersionQueryParam: Query parameter naming the API version (default: disabled)
+//   - DefaultVersion: Version used when a request names none (default: none, which selects
+//     the unversioned or first registered handler)
+//   - KeepAlive: Keep connections open between requests as HTTP/1.1 and HTTP/1.0
+//     keep-alive clients expect (default: true)
+//   - ReadHeaderTimeout: Time allowed for receiving the request line and headers once a
+//     request has started (default: 10 seconds); a client that does not finish in time
+//     gets 408 Request Timeout. A new connection that sends nothing for this long is
+//     closed without a response
+//   - ReadBodyTimeout: Time allowed for receiving the request body (default: 30 seconds);
+//     a client that does not finish in time gets 408 Request Timeout
+//   - HandlerTimeout: Time allowed for the middleware and handler of a route (default: 0,
+//     unlimited); when it runs out, RouteRequest.Context is cancelled and the client gets
+//     503 Service Unavailable
+//   - WriteTimeout: Time allowed for sending a response, including a streamed body
+//     (default: 30 seconds); the connection is closed when it runs out
+//   - IdleTimeout: How long a kept-alive connection may wait for its next request before it
+//     is closed (default: 60 seconds)
+//   - MaxRequestsPerConn: Number of requests after which a connection is closed (default: 0,
+//     unlimited)
+//   - Limits: Maximum sizes of the request line, headers and body; requests exceeding them
+//     are answered with 414, 431 or 413 (default: see RequestLimits)
+//
+// A timeout of zero disables it. When the application serves through ServeHTTP, only
+// HandlerTimeout applies; the others are configured on the http.Server instead.
+//
+// The three error handlers use the same RouteHandler shape as registered routes, so they run
+// their middleware first and can return the application's usual error envelope.
 //
 // The Application uses a worker pool architecture where a configurable number of
 // goroutines handle incoming requests concurrently, providing excellent performance
@@ -82,6 +132,32 @@ type Application[RouteState RouteStateCompatible] struct {
 	Context          context.Context
 	WorkerCount      int32
 	LogRequestsLevel int
+
+	NotFoundHandler         *RouteHandler[RouteState]
+	MethodNotAllowedHandler *RouteHandler[RouteState]
+	BadRequestHandler       *RouteHandler[RouteState]
+
+	DecodePath    bool
+	CleanPath     bool
+	TrailingSlash TrailingSlashPolicy
+
+	VersionHeader     string
+	VersionMediaType  string
+	VersionQueryParam string
+	DefaultVersion    string
+
+	KeepAlive          bool
+	MaxRequestsPerConn int
+
+	ReadHeaderTimeout time.Duration
+	ReadBodyTimeout   time.Duration
+	HandlerTimeout    time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	Limits RequestLimits
+
+	hosts []virtualHost[RouteState]
 }
 
 // NewInlineApplication creates a new Application instance with a custom context.
@@ -120,6 +196,17 @@ func NewInlineApplication[RouteState any](port string, db *sql.DB, ctx context.C
 		WorkerCount:      10,
 		Context:          ctx,
 		LogRequestsLevel: 0,
+		DecodePath:       true,
+		CleanPath:        true,
+		TrailingSlash:    TrailingSlashIgnore,
+		VersionHeader:    "Accept-Version",
+		KeepAlive:        true,
+		Limits:           defaultRequestLimits,
+
+		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		ReadBodyTimeout:   defaultReadBodyTimeout,
+		WriteTimeout:      defaultWriteTimeout,
+		IdleTimeout:       defaultIdleTimeout,
 	}
 }
 
@@ -158,18 +245,32 @@ func NewApplication[RouteState any](port string, db *sql.DB) *Application[RouteS
 		WorkerCount:      10,
 		Context:          ctx,
 		LogRequestsLevel: 0,
+		DecodePath:       true,
+		CleanPath:        true,
+		TrailingSlash:    TrailingSlashIgnore,
+		VersionHeader:    "Accept-Version",
+		KeepAlive:        true,
+		Limits:           defaultRequestLimits,
+
+		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		ReadBodyTimeout:   defaultReadBodyTimeout,
+		WriteTimeout:      defaultWriteTimeout,
+		IdleTimeout:       defaultIdleTimeout,
 	}
 }
 
-// AddRouteGroup registers all routes from a RouteGroup under a common prefix.
-// This method enables modular route organization by allowing you to define
-// related routes in groups and then mount them at specific path prefixes.
+// AddRouteGroup registers all routes from a RouteGroup, including its nested subgroups,
+// under a common prefix. This method enables modular route organization by allowing you
+// to define related routes in groups and then mount them at specific path prefixes.
 //
 // The method automatically handles path normalization:
 //   - Ensures the prefix starts with "/"
 //   - Ensures the prefix ends with "/"
 //   - Removes leading "/" from individual routes to prevent double slashes
-//   - Preserves middleware configuration for each route
+//   - Preserves middleware configuration and route names for each route
+//
+// Group middleware added with RouteGroup.Use runs before the route's own middleware,
+// outermost group first. See RouteCollection.AddRouteGroup for details.
 //
 // This is particularly useful for:
 //   - API versioning (e.g., "/v1", "/v2")
@@ -180,6 +281,10 @@ func NewApplication[RouteState any](port string, db *sql.DB) *Application[RouteS
 //   - prefix: URL path prefix for all routes in the group (e.g., "/api", "/v1")
 //   - rg: RouteGroup containing routes and their associated middleware
 //
+// Returns:
+//   - error: The first route that could not be registered, in which case none of the
+//     group's routes are registered; see RouteCollection.AddRouteHandler
+//
 // Example:
 //
 //	userRoutes := pilot.NewRouteGroup(
@@ -188,18 +293,34 @@ func NewApplication[RouteState any](port string, db *sql.DB) *Application[RouteS
 //	)
 //	app.AddRouteGroup("/user", userRoutes)
 //	// Creates: /user/profile, /user/settings
-func (a *Application[RouteState]) AddRouteGroup(prefix string, rg *RouteGroup[RouteState]) {
-	if !strings.HasPrefix(prefix, "/") {
-		prefix = "/" + prefix
-	}
-	if !strings.HasSuffix(prefix, "/") {
-		prefix += "/"
-	}
-	for i := range (*rg).Routes {
-		route := (*rg).Routes[i].Route
-		route = strings.TrimPrefix(route, "/")
-		a.Routes.AddRouteWithMiddleware((*rg).Routes[i].Method, prefix+route, (*rg).Routes[i].Handler, (*rg).Routes[i].Middleware)
-	}
+func (a *Application[RouteState]) AddRouteGroup(prefix string, rg *RouteGroup[RouteState]) error {
+	return a.Routes.AddRouteGroup(prefix, rg)
+}
+
+// RemoveRouteGroup unregisters every route of a group previously mounted with AddRouteGroup
+// at the same prefix, atomically. It can be called while the application is serving requests.
+// See RouteCollection.RemoveRouteGroup for details.
+//
+// Example:
+//
+//	app.AddRouteGroup("/beta", betaRoutes)
+//	// ...once the beta ends:
+//	app.RemoveRouteGroup("/beta", betaRoutes)
+func (a *Application[RouteState]) RemoveRouteGroup(prefix string, rg *RouteGroup[RouteState]) error {
+	return a.Routes.RemoveRouteGroup(prefix, rg)
+}
+
+// URL builds the path of a named route registered on this application.
+// See RouteCollection.URL for the parameter format and error conditions.
+//
+// Example:
+//
+//	app.AddRouteGroup("/api", pilot.NewRouteGroup(
+//	    pilot.GetRoute("/users/:id", getUser).Named("user.show"),
+//	))
+//	location, err := app.URL("user.show", "id", 42) // "/api/users/42"
+func (a *Application[RouteState]) URL(name string, params ...any) (string, error) {
+	return a.Routes.URL(name, params...)
 }
 
 // Start begins listening for HTTP requests and blocks until the application context
@@ -207,7 +328,8 @@ func (a *Application[RouteState]) AddRouteGroup(prefix string, rg *RouteGroup[Ro
 // and handles the complete request lifecycle including graceful shutdown.
 //
 // The server architecture uses a two-stage queuing system:
-//  1. A receiver goroutine accepts connections and queues them
+//  1. A receiver goroutine accepts connections and queues them once their first bytes
+//     arrive, so clients that connect without sending a request never occupy a worker
 //  2. Worker goroutines process requests from the queue concurrently
 //
 // This design provides several benefits:
@@ -241,14 +363,18 @@ func (a *Application[RouteState]) Start() {
 	if !a.SilentMode {
 		fmt.Printf("Starting server on port %v.\n\nRegistered routes:\n", a.Port)
 		a.Routes.PrintTree()
+		for i := range a.hosts {
+			fmt.Printf("\nHost %v:\n", a.hosts[i].pattern)
+			a.hosts[i].routes.PrintTree()
+		}
 	}
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", a.Port))
 	if err != nil {
 		panic(err)
 	}
 	var wg sync.WaitGroup
-	queue := make(chan net.Conn, a.WorkerCount*10)
-	recvQueue := make(chan net.Conn, a.WorkerCount*10)
+	queue := make(chan *connection, a.WorkerCount*10)
+	recvQueue := make(chan *connection, a.WorkerCount*10)
 	for i := int32(0); i < a.WorkerCount; i++ {
 		wg.Add(1)
 		go func() {
@@ -269,7 +395,7 @@ func (a *Application[RouteState]) Start() {
 			if (*a).LogRequestsLevel > 1 {
 				log.Printf("{reciever} Dispatching connection from %s\n", conn.RemoteAddr().String())
 			}
-			recvQueue <- conn
+			a.acceptConnection(a.Context, conn, recvQueue)
 		}
 	}()
 	func() {
@@ -307,17 +433,20 @@ func handlerLog(id int32, connId int64, ip net.Addr, msg string) {
 //
 // The function implements a complete HTTP request processing pipeline:
 //  1. Parse incoming HTTP request from TCP connection
-//  2. Handle CORS preflight OPTIONS requests automatically
-//  3. Route request to appropriate handler based on path and method
-//  4. Execute middleware chain with early termination support
-//  5. Call route handler with typed state and database access
-//  6. Apply CORS headers and send response
-//  7. Log request processing (based on LogRequestsLevel configuration)
+//  2. Resolve the request to a response with serveRequest
+//  3. Send the response, then either close the connection or hand it to an idle watcher
+//     that queues it again once the next request arrives (see KeepAlive)
+//  4. Log request processing (based on LogRequestsLevel configuration)
+//
+// A worker only holds a connection while a request is being read and answered, so idle
+// keep-alive connections never occupy the worker pool.
 //
 // Error Handling:
-//   - Invalid requests are logged and connections closed gracefully
-//   - Missing routes return 404 responses
-//   - Handler errors are caught and return 500 responses
+//   - Malformed requests are answered by serveMalformedRequest, requests exceeding the
+//     Limits with 414 or 431, and requests whose headers are not received within
+//     ReadHeaderTimeout with 408, then the connection is closed
+//   - Connections that close or time out before a full request are closed without a response
+//   - Routing errors (404, 405) and nil handler responses are handled by serveRequest
 //   - Network errors are handled without crashing the worker
 //
 // Context Management:
@@ -326,98 +455,265 @@ func handlerLog(id int32, connId int64, ip net.Addr, msg string) {
 //   - Maintains worker lifecycle through context monitoring
 //
 // Parameters:
-//   - conn: Channel receiving TCP connections to process
+//   - conn: Channel receiving connections to process; kept-alive connections are sent back
+//     to it when their next request arrives
 //   - app: Application instance with configuration and routes
 //   - cn: Context for cancellation and timeout control
 //   - id: Unique worker identifier for logging and monitoring
-func handleRequest[RouteState any](conn <-chan net.Conn, app *Application[RouteState], cn context.Context, id int32) {
+func handleRequest[RouteState any](conn chan *connection, app *Application[RouteState], cn context.Context, id int32) {
 	var connId int64 = 0
 	log.Printf("Worker #%d online, ready for requests.", id)
-ReqLoop:
 	for {
 		select {
 		case <-cn.Done():
 			log.Printf("Worker #%d shutdown.", id)
 			return
-		case conn := <-conn:
+		case c := <-conn:
 			connId++
 			if (*app).LogRequestsLevel > 1 {
-				handlerLog(id, connId, conn.RemoteAddr(), "Request dispatched.")
+				handlerLog(id, connId, c.RemoteAddr(), "Request dispatched.")
 			}
-			request := ParseRequest(&conn)
-			if request == nil {
-				handlerLog(id, connId, conn.RemoteAddr(), "Could not parse request.")
-				conn.Close()
-				continue ReqLoop
+			logf := func(msg string) {
+				handlerLog(id, connId, c.RemoteAddr(), msg)
 			}
+			request, err := readRequest(c.reader, c, app.Limits, app.ReadHeaderTimeout, app.ReadBodyTimeout)
+			if err != nil {
+				logf("Could not parse request: " + err.Error())
+				if request != nil {
+					response := app.serveRequestError(cn, request, err, logf)
+					response.SetHeader("Connection", "close")
+					c.SetWriteDeadline(deadline(app.WriteTimeout))
+					response.Write(c)
+				}
+				c.Close()
+				continue
+			}
+			c.requests++
 			if (*app).LogRequestsLevel > 0 {
-				handlerLog(id, connId, conn.RemoteAddr(), fmt.Sprintf("%s: '%s'", request.Method, request.Path))
+				logf(fmt.Sprintf("%s: '%s'", request.Method, request.Path))
 			}
 
-			if request.Method == Options {
-				response := HttpResponse{
-					StatusCode: StatusOK,
-					Body:       []byte{},
-					Headers: map[string]string{
-						"Access-Control-Allow-Origin":  (*app).CorsOrigin,
-						"Access-Control-Allow-Headers": (*app).CorsHeaders,
-						"Access-Control-Allow-Methods": (*app).CorsMethods,
-					},
-				}
-				response.Write(conn)
-				conn.Close()
-				continue ReqLoop
+			response := app.serveRequest(cn, request, logf)
+			// A body the handler never needed is read and dropped so the next request on
+			// the connection can be parsed; one over the limit closes the connection.
+			request.loadBody(app.Limits.MaxBodyBytes)
+			response.omitBody = request.Method == Head
+			response.untilClose = response.stream != nil && request.Proto == "HTTP/1.0"
+			keepAlive := app.keepAlive(cn, request, response, c.requests)
+			response.setConnectionHeader(request, keepAlive)
+			c.SetWriteDeadline(deadline(app.WriteTimeout))
+			if err := response.write(c); err != nil {
+				logf("Could not send response: " + err.Error())
+				keepAlive = false
 			}
-			response := StringResponse("")
-			response.Body = []byte("404 not found")
-			response.SetStatus(StatusNotFound)
-			response.ApplyCors(&app.CorsOrigin, &app.CorsHeaders, &app.CorsMethods)
-			route := (*app).Routes.FindPath(request.Path, false)
-			if route == nil {
-				response.Write(conn)
-				if (*app).LogRequestsLevel > 1 {
-					handlerLog(id, connId, conn.RemoteAddr(), "No route found.")
-				}
-				conn.Close()
-				continue ReqLoop
-			}
-			handler, found := route.Handlers[request.Method]
-			if !found {
-				response.Write(conn)
-				if (*app).LogRequestsLevel > 1 {
-					handlerLog(id, connId, conn.RemoteAddr(), "No handler found.")
-				}
-				conn.Close()
-				continue ReqLoop
+			if keepAlive {
+				go app.awaitRequest(cn, c, conn, app.IdleTimeout)
+			} else {
+				c.Close()
 			}
+		}
+	}
+}
 
-			var routeState RouteState
+// serveRequest resolves a parsed request into the response that should be sent to the client.
+// This covers everything between parsing and writing: routing, CORS preflight handling,
+// middleware execution and handler dispatch.
+//
+// Routing outcomes:
+//   - A method that is neither standard nor registered with RegisterMethod is answered
+//     with 501 Not Implemented before routing
+//   - The path is decoded and cleaned first, see DecodePath and CleanPath; a path with an
+//     invalid percent-encoding is answered like a malformed request
+//   - The route collection is chosen by the request's Host header, see Application.Host
+//   - A trailing slash that differs from the registered route is handled per TrailingSlash
+//   - No route matches the path: NotFoundHandler, or a plain 404 Not Found
+//   - The route exists but has no handler for the method: MethodNotAllowedHandler, or
+//     a plain 405 Method Not Allowed; either way the response carries an Allow header
+//     listing the methods the route does support unless the handler set one
+//   - The method has versioned handlers: the handler is selected by the requested API
+//     version, see VersionHeader; an explicitly requested version with no handler and no
+//     unversioned fallback is answered with 406 Not Acceptable
+//   - OPTIONS without an explicit OPTIONS handler: an automatic preflight response
+//     whose Allow and Access-Control-Allow-Methods headers list the route's methods
+//   - HEAD without an explicit HEAD handler: dispatched to the GET handler; the
+//     caller is responsible for leaving the body out when writing the response
+//
+// Parameters:
+//   - cn: Context passed through to the route handler
+//   - request: The parsed request; its Params are filled in from the matched route
+//   - logf: Receives diagnostic messages for this request
+//
+// Returns:
+//   - *HttpResponse: The response with CORS headers applied, never nil
+func (a *Application[RouteState]) serveRequest(cn context.Context, request *HttpRequest, logf func(string)) *HttpResponse {
+	if !request.Method.isImplemented() {
+		if a.LogRequestsLevel > 1 {
+			logf("Method not implemented.")
+		}
+		return a.serveNotImplemented()
+	}
+	if err := a.normalizePath(request); err != nil {
+		logf(err.Error())
+		return a.serveMalformedRequest(cn, request, logf)
+	}
+	routes, subdomain := a.routesForHost(request.Headers.Get("Host"))
+	request.Subdomain = subdomain
+	route, params := routes.MatchPathAppend(request.Path, request.paramBuffer[:0])
+	if route != nil && a.TrailingSlash != TrailingSlashIgnore && hasTrailingSlash(request.Path) != route.trailingSlash {
+		if a.TrailingSlash == TrailingSlashRedirect {
+			return a.trailingSlashRedirect(request, route.trailingSlash)
+		}
+		route = nil
+	}
+	if route == nil {
+		if a.LogRequestsLevel > 1 {
+			logf("No route found.")
+		}
+		if a.NotFoundHandler != nil {
+			return a.runHandler(cn, request, a.NotFoundHandler, logf)
+		}
+		response := StringResponse("404 not found")
+		response.SetStatus(StatusNotFound)
+		response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+		return response
+	}
+	if a.DecodePath {
+		unescapeParams(params)
+	}
+	request.Params = params
+	handler, found := route.handlerFor(request.Method)
+	if !found && request.Method == Options {
+		allow := strings.Join(route.AllowedMethods(), ", ")
+		response := NewHttpResponse()
+		response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &allow)
+		response.SetHeader("Allow", allow)
+		return response
+	}
+	if !found {
+		if a.LogRequestsLevel > 1 {
+			logf("No handler found.")
+		}
+		var response *HttpResponse
+		if a.MethodNotAllowedHandler != nil {
+			response = a.runHandler(cn, request, a.MethodNotAllowedHandler, logf)
+		} else {
+			response = StringResponse("405 method not allowed")
+			response.SetStatus(StatusMethodNotAllowed)
+			response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+		}
+		if !response.Headers.Has("Allow") {
+			response.SetHeader("Allow", strings.Join(route.AllowedMethods(), ", "))
+		}
+		return response
+	}
+	if len(route.Versions) == 0 {
+		return a.runHandler(cn, request, &handler, logf)
+	}
+	version, explicit := a.requestedVersion(request)
+	handler, found = route.versionFor(request.Method, handler, version, explicit)
+	var response *HttpResponse
+	if found {
+		response = a.runHandler(cn, request, &handler, logf)
+	} else {
+		if a.LogRequestsLevel > 1 {
+			logf("No handler for version " + version + ".")
+		}
+		response = StringResponse("406 version not acceptable")
+		response.SetStatus(StatusNotAcceptable)
+		response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	}
+	a.varyByVersion(response)
+	return response
+}
 
-			routeData := RouteRequest[RouteState]{
-				Context:  cn,
-				Request:  request,
-				Database: app.Database,
-				State:    &routeState,
-			}
+// serveMalformedRequest builds the response for a request that could not be parsed,
+// using BadRequestHandler when one is configured. The request only contains the parts
+// that were parsed before the error, so the hook should rely on little more than IpAddress.
+func (a *Application[RouteState]) serveMalformedRequest(cn context.Context, request *HttpRequest, logf func(string)) *HttpResponse {
+	if a.BadRequestHandler != nil {
+		return a.runHandler(cn, request, a.BadRequestHandler, logf)
+	}
+	response := StringResponse("400 bad request")
+	response.SetStatus(StatusBadRequest)
+	response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	return response
+}
 
-			for i := range handler.Middleware {
-				response = handler.Middleware[i](&routeData)
-				if response != nil {
-					response.ApplyCors(&app.CorsOrigin, &app.CorsHeaders, &app.CorsMethods)
-					response.Write(conn)
-					conn.Close()
-					continue ReqLoop
-				}
-			}
+// serveNotImplemented builds the 501 response for a request using a method or transfer
+// coding the server does not support.
+func (a *Application[RouteState]) serveNotImplemented() *HttpResponse {
+	response := StringResponse("501 not implemented")
+	response.SetStatus(StatusNotImplemented)
+	response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	return response
+}
 
-			response = handler.Handler(&routeData)
-			if response == nil {
-				handlerLog(id, connId, conn.RemoteAddr(), "Handler returned nil, sending 500.")
-				response = StringResponse("500 Internal Server Error")
-			}
-			response.ApplyCors(&app.CorsOrigin, &app.CorsHeaders, &app.CorsMethods)
-			response.Write(conn)
-			conn.Close()
+// serveRequestError builds the response for a request that could not be read: 413, 414 or
+// 431 if it exceeded the Limits, 408 if it was not received in time, 501 if its body uses
+// an unsupported transfer coding, otherwise the response of serveMalformedRequest.
+func (a *Application[RouteState]) serveRequestError(cn context.Context, request *HttpRequest, err error, logf func(string)) *HttpResponse {
+	var limitErr *limitError
+	if errors.As(err, &limitErr) {
+		return a.serveLimitExceeded(limitErr)
+	}
+	if isTimeout(err) {
+		return a.serveTimeout()
+	}
+	if errors.Is(err, ErrUnsupportedTransferCoding) {
+		return a.serveNotImplemented()
+	}
+	return a.serveMalformedRequest(cn, request, logf)
+}
+
+// runHandler executes a handler's middleware chain and the handler itself with fresh
+// route state, stopping at the first middleware that returns a response. A nil handler
+// response becomes a 500. Deprecation headers from the handler's metadata and CORS headers
+// are applied to whatever response is produced.
+//
+// The request body is read first, within the handler's limits (see RequestLimits); a
+// request exceeding them, or whose body cannot be read, is answered without running
+// the handler. The middleware and handler together must finish within HandlerTimeout.
+func (a *Application[RouteState]) runHandler(cn context.Context, request *HttpRequest, handler *RouteHandler[RouteState], logf func(string)) *HttpResponse {
+	limits := a.Limits.override(handler.Limits)
+	err := limits.checkHead(request)
+	if err == nil {
+		err = request.loadBody(limits.MaxBodyBytes)
+	}
+	if err != nil {
+		logf("Could not read request: " + err.Error())
+		if request.bodyErr == nil {
+			request.bodyErr = err
 		}
+		return a.serveRequestError(cn, request, err, logf)
 	}
+
+	response := a.runWithTimeout(cn, logf, func(cn context.Context) *HttpResponse {
+		var routeState RouteState
+
+		routeData := RouteRequest[RouteState]{
+			Context:  cn,
+			Request:  request,
+			Database: a.Database,
+			State:    &routeState,
+			Metadata: handler.Metadata,
+			Version:  handler.Version,
+		}
+
+		for i := range handler.Middleware {
+			if response := handler.Middleware[i](&routeData); response != nil {
+				return response
+			}
+		}
+
+		response := handler.Handler(&routeData)
+		if response == nil {
+			logf("Handler returned nil, sending 500.")
+			response = StringResponse("500 Internal Server Error")
+			response.SetStatus(StatusInternalServerError)
+		}
+		return response
+	})
+	handler.Metadata.applyHeaders(response)
+	response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	return response
 }
@@ -0,0 +1,142 @@
+package pilot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// readChunked decodes a request body sent with "Transfer-Encoding: chunked" (RFC 9112,
+// section 7.1). Chunk extensions are ignored, and the trailer fields that follow the last
+// chunk are returned separately from the body.
+//
+// Parameters:
+//   - reader: Connection reader positioned at the start of the body
+//   - limit: Maximum length of the decoded body; zero or less means no limit
+//   - lineLimit: Maximum length of each chunk size line and of the trailer section;
+//     zero or less means no limit
+//
+// Returns:
+//   - []byte: The decoded body
+//   - Header: Trailer fields, or nil if the client sent none
+//   - error: A wrapped ErrMalformedRequest for invalid chunk syntax or a chunk size line
+//     over lineLimit, a *limitError when the body or trailer section is too long, or the
+//     underlying read error when the connection closed or timed out
+func readChunked(reader *bufio.Reader, limit int64, lineLimit int) ([]byte, Header, error) {
+	lineTooLong := func() error {
+		return fmt.Errorf("%w: chunk line longer than %d bytes", ErrMalformedRequest, lineLimit)
+	}
+	trailersTooLong := func() error {
+		return tooLarge(StatusContentTooLarge, "trailer section longer than %d bytes", lineLimit)
+	}
+	var body bytes.Buffer
+	for {
+		line, err := readChunkLine(reader, lineLimit, lineTooLong)
+		if err != nil {
+			return nil, nil, err
+		}
+		sizeField, _, _ := strings.Cut(line, ";")
+		size, err := strconv.ParseUint(strings.TrimRight(sizeField, " \t"), 16, 63)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: invalid chunk size %q", ErrMalformedRequest, line)
+		}
+		if size == 0 {
+			break
+		}
+		if limit > 0 && int64(size) > limit-int64(body.Len()) {
+			return nil, nil, tooLarge(StatusContentTooLarge, "chunked body longer than %d bytes", limit)
+		}
+		if _, err := io.CopyN(&body, reader, int64(size)); err != nil {
+			return nil, nil, err
+		}
+		if line, err = readChunkLine(reader, lineLimit, lineTooLong); err != nil {
+			return nil, nil, err
+		} else if line != "" {
+			return nil, nil, fmt.Errorf("%w: chunk data longer than its size %d", ErrMalformedRequest, size)
+		}
+	}
+
+	var trailers Header
+	for remaining := lineLimit; ; {
+		line, err := readChunkLine(reader, remaining, trailersTooLong)
+		if err != nil {
+			return nil, nil, err
+		}
+		if lineLimit > 0 {
+			remaining = max(remaining-len(line)-2, 2)
+		}
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found || !isFieldName(name) {
+			return nil, nil, fmt.Errorf("%w: invalid trailer line %q", ErrMalformedRequest, line)
+		}
+		if trailers == nil {
+			trailers = Header{}
+		}
+		trailers.Add(name, strings.Trim(value, " \t"))
+	}
+	return body.Bytes(), trailers, nil
+}
+
+// readChunkLine reads one line of chunked framing, of at most limit bytes, without its
+// line terminator.
+func readChunkLine(reader *bufio.Reader, limit int, tooLong func() error) (string, error) {
+	line, err := readLine(reader, limit, tooLong)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
+
+// checkTransferEncoding validates the transfer codings of a request. Chunked must be the
+// final coding, since otherwise the body has no reliable end (RFC 9112, section 6.3), and
+// since no other coding is supported, a request using one is answered with 501 Not
+// Implemented (RFC 9112, section 6.1).
+func checkTransferEncoding(transferEncoding string) error {
+	codings := strings.Split(transferEncoding, ",")
+	if !isChunked(codings[len(codings)-1]) {
+		return fmt.Errorf("%w: chunked is not the final Transfer-Encoding in %q", ErrMalformedRequest, transferEncoding)
+	}
+	if len(codings) > 1 {
+		return fmt.Errorf("%w: %q", ErrUnsupportedTransferCoding, transferEncoding)
+	}
+	return nil
+}
+
+// isChunked reports whether a transfer coding is chunked.
+func isChunked(coding string) bool {
+	return strings.EqualFold(strings.TrimSpace(coding), "chunked")
+}
+
+// chunkedWriter encodes everything written to it as chunks of a "Transfer-Encoding: chunked"
+// body. Each Write is sent to the underlying writer immediately as a single chunk, so
+// streamed data is never held back; Close writes the terminating zero-length chunk.
+type chunkedWriter struct {
+	w      io.Writer
+	buffer []byte
+}
+
+func (self *chunkedWriter) Write(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	self.buffer = strconv.AppendUint(self.buffer[:0], uint64(len(data)), 16)
+	self.buffer = append(self.buffer, "\r\n"...)
+	self.buffer = append(self.buffer, data...)
+	self.buffer = append(self.buffer, "\r\n"...)
+	if _, err := self.w.Write(self.buffer); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Close ends the body with the last chunk and an empty trailer section.
+func (self *chunkedWriter) Close() error {
+	_, err := io.WriteString(self.w, "0\r\n\r\n")
+	return err
+}
@@ -0,0 +1,100 @@
+package pilot
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+// connection is a client connection handed between the acceptor, the workers and the idle
+// watchers. The buffered reader lives as long as the connection, so bytes of a pipelined
+// request that were read together with the previous one are not lost.
+type connection struct {
+	net.Conn
+	reader   *bufio.Reader
+	requests int
+}
+
+// newConnection wraps an accepted connection.
+func newConnection(conn net.Conn) *connection {
+	return &connection{
+		Conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+// keepAlive reports whether the connection a request arrived on should stay open after the
+// response. HTTP/1.1 connections are persistent unless either side sends "Connection: close",
+// HTTP/1.0 connections only if the client asks for "Connection: keep-alive", and every
+// connection is closed once it has served MaxRequestsPerConn requests or the application
+// is shutting down. Streamed responses to HTTP/1.0 clients end by closing the connection,
+// as do requests whose body could not be read completely.
+func (a *Application[RouteState]) keepAlive(cn context.Context, request *HttpRequest, response *HttpResponse, served int) bool {
+	if !a.KeepAlive || response.untilClose || request.bodyErr != nil || cn.Err() != nil {
+		return false
+	}
+	if a.MaxRequestsPerConn > 0 && served >= a.MaxRequestsPerConn {
+		return false
+	}
+	if hasHeaderToken(response.Headers.joined("Connection"), "close") || hasHeaderToken(request.Headers.joined("Connection"), "close") {
+		return false
+	}
+	if request.Proto == "HTTP/1.0" {
+		return hasHeaderToken(request.Headers.joined("Connection"), "keep-alive")
+	}
+	return true
+}
+
+// setConnectionHeader tells the client whether the connection stays open: "close" when it
+// will be closed, and "keep-alive" for HTTP/1.0 clients, which otherwise assume it is closed.
+func (self *HttpResponse) setConnectionHeader(request *HttpRequest, keepAlive bool) {
+	if !keepAlive {
+		self.SetHeader("Connection", "close")
+	} else if request.Proto == "HTTP/1.0" {
+		self.SetHeader("Connection", "keep-alive")
+	}
+}
+
+// acceptConnection hands a newly accepted connection to awaitRequest, so it only reaches
+// a worker once the client starts sending its first request. The client has
+// ReadHeaderTimeout to do so.
+func (a *Application[RouteState]) acceptConnection(cn context.Context, conn net.Conn, queue chan<- *connection) {
+	go a.awaitRequest(cn, newConnection(conn), queue, a.ReadHeaderTimeout)
+}
+
+// awaitRequest waits, outside the worker pool, for the next request on a new or kept-alive
+// connection and queues the connection for a worker once its first bytes arrive. The
+// connection is closed if it stays idle for timeout, if the client closes it, or if the
+// application shuts down while waiting.
+func (a *Application[RouteState]) awaitRequest(cn context.Context, c *connection, queue chan<- *connection, timeout time.Duration) {
+	if c.reader.Buffered() == 0 {
+		c.SetReadDeadline(deadline(timeout))
+		stop := context.AfterFunc(cn, func() {
+			c.SetReadDeadline(time.Unix(1, 0))
+		})
+		_, err := c.reader.Peek(1)
+		stop()
+		if err != nil {
+			c.Close()
+			return
+		}
+	}
+	select {
+	case queue <- c:
+	case <-cn.Done():
+		c.Close()
+	}
+}
+
+// hasHeaderToken reports whether a comma-separated header value such as Connection contains
+// the token, compared case-insensitively.
+func hasHeaderToken(value string, token string) bool {
+	for _, part := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package pilot
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// startTestConnection runs a worker for app and queues the server end of a pipe,
+// returning the client end and a reader for its responses.
+func startTestConnection(t *testing.T, app *Application[struct{}]) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	queue := make(chan *connection, 1)
+	go handleRequest(queue, app, ctx, 0)
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	queue <- newConnection(server)
+	return client, bufio.NewReader(client)
+}
+
+func readTestResponse(t *testing.T, reader *bufio.Reader) *http.Response {
+	t.Helper()
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(response.Body)
+	response.Body.Close()
+	return response
+}
+
+func assertClosed(t *testing.T, client net.Conn, reader *bufio.Reader) {
+	t.Helper()
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := reader.ReadByte(); err != io.EOF {
+		t.Errorf("connection was not closed: %v", err)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	newApp := func() *Application[struct{}] {
+		app := newTestApplication()
+		app.Routes.AddRoute(Get, "/", noopHandler)
+		return app
+	}
+
+	t.Run("http/1.1", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "GET / HTTP/1.1\r\nHost: a\r\n\r\nGET / HTTP/1.1\r\nHost: a\r\n\r\n")
+		for range 2 {
+			if response := readTestResponse(t, reader); response.StatusCode != 200 || response.Close {
+				t.Fatalf("pipelined response = %d, close %v", response.StatusCode, response.Close)
+			}
+		}
+		io.WriteString(client, "GET / HTTP/1.1\r\nConnection: close\r\n\r\n")
+		if response := readTestResponse(t, reader); !response.Close {
+			t.Error("response to Connection: close did not close the connection")
+		}
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("chunked body", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\nGET / HTTP/1.1\r\n\r\n")
+		if response := readTestResponse(t, reader); response.StatusCode != int(StatusMethodNotAllowed) {
+			t.Errorf("POST = %d", response.StatusCode)
+		}
+		if response := readTestResponse(t, reader); response.StatusCode != 200 {
+			t.Errorf("request after a chunked body = %d", response.StatusCode)
+		}
+	})
+
+	t.Run("unsupported transfer coding", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "POST / HTTP/1.1\r\nTransfer-Encoding: gzip, chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\n")
+		if response := readTestResponse(t, reader); response.StatusCode != int(StatusNotImplemented) || !response.Close {
+			t.Errorf("gzip, chunked = %d, close %v", response.StatusCode, response.Close)
+		}
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("http/1.0", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "GET / HTTP/1.0\r\nConnection: keep-alive\r\n\r\n")
+		if response := readTestResponse(t, reader); response.Header.Get("Connection") != "keep-alive" {
+			t.Errorf("Connection = %q, want keep-alive", response.Header.Get("Connection"))
+		}
+		io.WriteString(client, "GET / HTTP/1.0\r\n\r\n")
+		readTestResponse(t, reader)
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("max requests", func(t *testing.T) {
+		app := newApp()
+		app.MaxRequestsPerConn = 2
+		client, reader := startTestConnection(t, app)
+		io.WriteString(client, "GET / HTTP/1.1\r\n\r\n")
+		readTestResponse(t, reader)
+		io.WriteString(client, "GET / HTTP/1.1\r\n\r\n")
+		if response := readTestResponse(t, reader); !response.Close {
+			t.Error("connection kept alive past MaxRequestsPerConn")
+		}
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("idle timeout", func(t *testing.T) {
+		app := newApp()
+		app.IdleTimeout = 20 * time.Millisecond
+		client, reader := startTestConnection(t, app)
+		io.WriteString(client, "GET / HTTP/1.1\r\n\r\n")
+		readTestResponse(t, reader)
+		assertClosed(t, client, reader)
+	})
+}
+
+func TestIdleConnectionDoesNotHoldWorker(t *testing.T) {
+	app := newTestApplication()
+	app.ReadHeaderTimeout = 5 * time.Second
+	app.Routes.AddRoute(Get, "/", noopHandler)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := make(chan *connection)
+	go handleRequest(queue, app, ctx, 0)
+
+	idleServer, idleClient := net.Pipe()
+	defer idleClient.Close()
+	app.acceptConnection(ctx, idleServer, queue)
+
+	server, client := net.Pipe()
+	defer client.Close()
+	app.acceptConnection(ctx, server, queue)
+	go io.WriteString(client, "GET / HTTP/1.1\r\n\r\n")
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	response, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatalf("second client was not served while another connection was idle: %v", err)
+	}
+	if response.StatusCode != 200 {
+		t.Errorf("GET / = %d", response.StatusCode)
+	}
+}
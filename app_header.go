@@ -0,0 +1,84 @@
+package pilot
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+// Header holds the header fields of a request or response. Field names are case-insensitive,
+// so the methods canonicalize them ("content-length" becomes "Content-Length"), and a field
+// can carry several values, such as multiple Set-Cookie headers on one response.
+//
+// Header has the same representation as net/http.Header and can be converted to and from it.
+// Reading or writing the map directly bypasses canonicalization; use the methods instead.
+//
+// Example:
+//
+//	token := req.Request.Headers.Get("authorization")
+//
+//	response := pilot.StringResponse("ok")
+//	response.Headers.Add("Set-Cookie", "session=abc; HttpOnly")
+//	response.Headers.Add("Set-Cookie", "theme=dark")
+type Header map[string][]string
+
+// Get returns the first value of the named field, or "" if it is not set.
+func (h Header) Get(key string) string {
+	return textproto.MIMEHeader(h).Get(key)
+}
+
+// Values returns all values of the named field in the order they were added.
+// The returned slice is not a copy.
+func (h Header) Values(key string) []string {
+	return textproto.MIMEHeader(h).Values(key)
+}
+
+// Has reports whether the named field is set, even if its value is empty.
+func (h Header) Has(key string) bool {
+	_, found := h[textproto.CanonicalMIMEHeaderKey(key)]
+	return found
+}
+
+// Add appends a value to the named field, keeping any existing values.
+func (h Header) Add(key string, value string) {
+	textproto.MIMEHeader(h).Add(key, value)
+}
+
+// Set replaces all values of the named field with a single value.
+func (h Header) Set(key string, value string) {
+	textproto.MIMEHeader(h).Set(key, value)
+}
+
+// Del removes the named field.
+func (h Header) Del(key string) {
+	textproto.MIMEHeader(h).Del(key)
+}
+
+// Clone returns a copy of the header that can be modified independently.
+func (h Header) Clone() Header {
+	if h == nil {
+		return nil
+	}
+	clone := make(Header, len(h))
+	for key, values := range h {
+		clone[key] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// joined returns all values of the named field joined with ", ", the form in which a field
+// repeated over several lines is equivalent to a single comma-separated line.
+func (h Header) joined(key string) string {
+	return strings.Join(h.Values(key), ", ")
+}
+
+// isFieldName reports whether s is a valid header field name: a non-empty token without
+// whitespace, which in particular rejects "Name :" with space before the colon.
+func isFieldName(s string) bool {
+	return isMethodToken(s)
+}
+
+// isFramingHeader reports whether a response field describes the message framing. These
+// fields are computed when the response is written, so values set by handlers are ignored.
+func isFramingHeader(key string) bool {
+	return key == "Content-Length" || key == "Transfer-Encoding"
+}
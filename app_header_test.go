@@ -0,0 +1,41 @@
+package pilot
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestHeader(t *testing.T) {
+	header := Header{}
+	header.Set("content-type", "text/plain")
+	header.Add("SET-COOKIE", "a=1")
+	header.Add("Set-Cookie", "b=2")
+
+	if got := header.Get("Content-Type"); got != "text/plain" {
+		t.Errorf("Get(Content-Type) = %q", got)
+	}
+	if got := header.Values("set-cookie"); !slices.Equal(got, []string{"a=1", "b=2"}) {
+		t.Errorf("Values(set-cookie) = %q", got)
+	}
+	if !header.Has("content-TYPE") || header.Has("Accept") {
+		t.Errorf("Has reported %v", header)
+	}
+
+	clone := header.Clone()
+	clone.Set("Set-Cookie", "c=3")
+	if got := header.Values("Set-Cookie"); len(got) != 2 {
+		t.Errorf("modifying the clone changed the original: %q", got)
+	}
+	if got := clone.Values("Set-Cookie"); !slices.Equal(got, []string{"c=3"}) {
+		t.Errorf("Set on clone = %q", got)
+	}
+
+	header.Del("set-cookie")
+	if header.Has("Set-Cookie") || header.Get("Set-Cookie") != "" {
+		t.Errorf("Del left %q", header.Values("Set-Cookie"))
+	}
+	if got := http.Header(header).Get("Content-Type"); got != "text/plain" {
+		t.Errorf("http.Header conversion = %q", got)
+	}
+}
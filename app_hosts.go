@@ -0,0 +1,93 @@
+package pilot
+
+import (
+	"strings"
+)
+
+// virtualHost pairs a host pattern with the route collection serving it.
+type virtualHost[RouteState RouteStateCompatible] struct {
+	pattern string
+	routes  *RouteCollection[RouteState]
+}
+
+// Host returns the route collection that serves requests for the given host pattern,
+// creating it on first use. This allows a single Application to serve several hostnames,
+// each with its own independent set of routes.
+//
+// Patterns are matched against the request's Host header, ignoring case and port:
+//   - "api.example.com" matches that exact hostname
+//   - "*.tenant.example.com" matches exactly one additional label, such as
+//     "acme.tenant.example.com"; the matched label is available to handlers
+//     as HttpRequest.Subdomain
+//
+// Exact patterns take priority over wildcards, and longer wildcard suffixes take
+// priority over shorter ones. Requests whose host matches no pattern are served
+// by Application.Routes.
+//
+// Host patterns must be declared before Start. The returned collection is safe for
+// concurrent use, so its routes can still be changed while requests are being served.
+//
+// Parameters:
+//   - pattern: Hostname or wildcard hostname pattern
+//
+// Returns:
+//   - *RouteCollection[RouteState]: The collection for this host, ready for route registration
+//
+// Example:
+//
+//	app.Host("api.example.com").AddRoute(pilot.Get, "/status", apiStatus)
+//	app.Host("*.tenant.example.com").AddRoute(pilot.Get, "/", func(req *pilot.RouteRequest[AppState]) *pilot.HttpResponse {
+//	    return pilot.StringResponse("Welcome, " + req.Request.Subdomain)
+//	})
+func (a *Application[RouteState]) Host(pattern string) *RouteCollection[RouteState] {
+	pattern = normalizeHost(pattern)
+	for i := range a.hosts {
+		if a.hosts[i].pattern == pattern {
+			return a.hosts[i].routes
+		}
+	}
+	routes := NewRouteCollection[RouteState]()
+	routes.Strict = a.Routes.Strict
+	a.hosts = append(a.hosts, virtualHost[RouteState]{pattern: pattern, routes: routes})
+	return routes
+}
+
+// routesForHost selects the route collection for a Host header value, returning the
+// matched wildcard label alongside it. Falls back to Application.Routes.
+func (a *Application[RouteState]) routesForHost(host string) (*RouteCollection[RouteState], string) {
+	if len(a.hosts) == 0 {
+		return a.Routes, ""
+	}
+	host = normalizeHost(host)
+	for i := range a.hosts {
+		if a.hosts[i].pattern == host {
+			return a.hosts[i].routes, ""
+		}
+	}
+	var best *virtualHost[RouteState]
+	label := ""
+	for i := range a.hosts {
+		suffix, wildcard := strings.CutPrefix(a.hosts[i].pattern, "*")
+		if !wildcard || (best != nil && len(best.pattern) >= len(a.hosts[i].pattern)) {
+			continue
+		}
+		if sub, found := strings.CutSuffix(host, suffix); found && sub != "" && !strings.Contains(sub, ".") {
+			best = &a.hosts[i]
+			label = sub
+		}
+	}
+	if best == nil {
+		return a.Routes, ""
+	}
+	return best.routes, label
+}
+
+// normalizeHost lowercases a host and strips any port and trailing dot, so that
+// "Example.COM:8080" and "example.com." both compare equal to "example.com".
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if colon := strings.LastIndexByte(host, ':'); colon > strings.LastIndexByte(host, ']') {
+		host = host[:colon]
+	}
+	return strings.TrimSuffix(host, ".")
+}
@@ -0,0 +1,49 @@
+package pilot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHostRouting(t *testing.T) {
+	app := newTestApplication()
+	respondWith := func(body string) RouteHandlerFn[struct{}] {
+		return func(req *RouteRequest[struct{}]) *HttpResponse {
+			return StringResponse(body + req.Request.Subdomain)
+		}
+	}
+	app.Routes.AddRoute(Get, "/", respondWith("default"))
+	app.Host("api.example.com").AddRoute(Get, "/", respondWith("api"))
+	app.Host("*.example.com").AddRoute(Get, "/", respondWith("example:"))
+	app.Host("*.tenant.example.com").AddRoute(Get, "/", respondWith("tenant:"))
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "api.example.com", want: "api"},
+		{host: "API.Example.com:8080", want: "api"},
+		{host: "www.example.com", want: "example:www"},
+		{host: "acme.tenant.example.com", want: "tenant:acme"},
+		{host: "a.b.tenant.example.com", want: "default"},
+		{host: "example.com", want: "default"},
+		{host: "", want: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			request := &HttpRequest{
+				Method:  Get,
+				Path:    "/",
+				Headers: Header{"Host": {tt.host}},
+			}
+			response := app.serveRequest(context.Background(), request, func(string) {})
+			if string(response.Body) != tt.want {
+				t.Errorf("Host %q served %q, want %q", tt.host, response.Body, tt.want)
+			}
+		})
+	}
+
+	if app.Host("API.example.com") != app.Host("api.example.com") {
+		t.Error("Host returned a different collection for the same pattern")
+	}
+}
@@ -0,0 +1,277 @@
+package pilot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// ServeHTTP implements net/http.Handler, so an Application can be mounted inside an existing
+// net/http server, tested with net/http/httptest, or placed behind the standard library's TLS
+// and HTTP/2 support instead of using Start's socket loop.
+//
+// The *http.Request is translated into an HttpRequest and dispatched through the same routing,
+// middleware and error handling as requests accepted by Start. The handler's context is the
+// request's context, so it is cancelled when the client disconnects. The resulting HttpResponse
+// is copied to the ResponseWriter; HEAD requests are handled by net/http, which discards the body.
+//
+// Parameters:
+//   - w: Response writer the HttpResponse is copied to
+//   - r: Incoming request; its body is read completely once the route is matched, within
+//     the route's body limit (see RequestLimits)
+//
+// Example:
+//
+//	app := pilot.NewApplication[AppState]("", db)
+//	app.Routes.AddRoute(pilot.Get, "/users/:id", getUser)
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/api/", http.StripPrefix("/api", app))
+//	http.ListenAndServeTLS(":443", "cert.pem", "key.pem", mux)
+func (a *Application[RouteState]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logf := func(msg string) {
+		log.Printf("(%s): %s\n", r.RemoteAddr, msg)
+	}
+	request := requestFromStd(r)
+	if a.LogRequestsLevel > 0 {
+		logf(string(request.Method) + ": '" + request.Path + "'")
+	}
+	response := a.serveRequest(r.Context(), request, logf)
+	response.writeStd(w)
+}
+
+// requestFromStd converts the request line and headers of a net/http request into an
+// HttpRequest. The Host header, which net/http removes from the header map, is restored
+// from r.Host. The body is read from r.Body, together with any trailers, once the route
+// and its body limit are known.
+func requestFromStd(r *http.Request) *HttpRequest {
+	request := &HttpRequest{
+		Path:        r.URL.EscapedPath(),
+		QueryString: r.URL.RawQuery,
+		Method:      HttpMethod(r.Method),
+		Proto:       r.Proto,
+		Headers:     Header(r.Header).Clone(),
+		IpAddress:   r.RemoteAddr,
+	}
+	if request.Headers == nil {
+		request.Headers = Header{}
+	}
+	if r.Host != "" {
+		request.Headers.Set("Host", r.Host)
+	}
+	request.lineBytes = len(r.Method) + len(r.RequestURI) + len(r.Proto) + 4
+	for key, values := range request.Headers {
+		for _, value := range values {
+			request.headerCount++
+			request.headerBytes += len(key) + len(value) + 4
+		}
+	}
+	request.readBody = func(limit int64) ([]byte, Header, error) {
+		if exceeds(limit, r.ContentLength) {
+			return nil, nil, tooLarge(StatusContentTooLarge, "Content-Length %d exceeds %d bytes", r.ContentLength, limit)
+		}
+		reader := io.Reader(r.Body)
+		if limit > 0 {
+			reader = io.LimitReader(r.Body, limit+1)
+		}
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exceeds(limit, int64(len(body))) {
+			return nil, nil, tooLarge(StatusContentTooLarge, "body longer than %d bytes", limit)
+		}
+		var trailers Header
+		if len(r.Trailer) > 0 {
+			trailers = Header(r.Trailer).Clone()
+		}
+		return body, trailers, nil
+	}
+	return request
+}
+
+// writeStd copies the response to a net/http ResponseWriter: headers first, then the
+// Content-Length and status, then the body or the streamed Writer contents. Streaming
+// responses leave the framing to net/http and flush after every write, and responses
+// whose status does not allow content are sent with their headers only.
+func (self *HttpResponse) writeStd(w http.ResponseWriter) {
+	defer self.releaseBody()
+	header := w.Header()
+	for key, values := range self.Headers {
+		if !isFramingHeader(key) {
+			header[key] = append(header[key], values...)
+		}
+	}
+	if !self.StatusCode.allowsBody() {
+		w.WriteHeader(int(self.StatusCode))
+		return
+	}
+	if self.stream != nil {
+		w.WriteHeader(int(self.StatusCode))
+		if err := self.stream(flushWriter{w}); err != nil {
+			panic(http.ErrAbortHandler)
+		}
+		return
+	}
+	if self.Writer != nil {
+		header.Set("Content-Length", strconv.FormatInt(self.WriterSize, 10))
+	} else {
+		header.Set("Content-Length", strconv.Itoa(len(self.Body)))
+	}
+	w.WriteHeader(int(self.StatusCode))
+	if self.Writer != nil {
+		self.Writer.WriteTo(w)
+	} else {
+		w.Write(self.Body)
+	}
+}
+
+// HttpHandler adapts a net/http.Handler into a route handler, so standard library and
+// third-party handlers can be registered in the route tree like any other handler.
+//
+// The HttpRequest is converted to an *http.Request using the path as the client sent it
+// (RawPath) and the route request's context, and the handler's output is buffered into an
+// HttpResponse. Because the response is buffered, streaming handlers only reach the client
+// once they return.
+//
+// Parameters:
+//   - handler: The net/http handler to adapt
+//
+// Returns:
+//   - RouteHandlerFn[RouteState]: A route handler that invokes handler for each request
+//
+// Example:
+//
+//	routes.AddRoute(pilot.Get, "/oauth/callback", pilot.HttpHandler[AppState](oauthConfig.CallbackHandler()))
+func HttpHandler[RouteState RouteStateCompatible](handler http.Handler) RouteHandlerFn[RouteState] {
+	return func(req *RouteRequest[RouteState]) *HttpResponse {
+		r, err := req.Request.toStd(req.Context)
+		if err != nil {
+			return ErrorResponse(err)
+		}
+		recorder := &responseRecorder{header: http.Header{}}
+		handler.ServeHTTP(recorder, r)
+		return recorder.response()
+	}
+}
+
+// Mount registers a net/http.Handler for every request below a path prefix, for all methods
+// except OPTIONS, which keeps being answered by Pilot's automatic preflight handling.
+// Requests still pass through the given middleware and receive the application's CORS headers.
+//
+// The handler sees the full original path, as net/http handlers mounted on a ServeMux do;
+// wrap it in http.StripPrefix if it expects paths relative to the prefix. The remainder of
+// the path after the prefix is also available as the "path" parameter.
+//
+// Parameters:
+//   - prefix: Path prefix to mount the handler at (e.g., "/debug/pprof")
+//   - handler: The net/http handler serving everything below the prefix
+//   - middleware: Middleware run before the handler, such as authentication
+//
+// Returns:
+//   - error: The first registration error, in which case nothing is mounted; see AddRouteHandler
+//
+// Example:
+//
+//	routes.Mount("/debug/pprof", http.HandlerFunc(pprof.Index), adminOnly)
+//	routes.Mount("/assets", http.StripPrefix("/assets", http.FileServer(http.Dir("./public"))))
+func (self *RouteCollection[RouteState]) Mount(prefix string, handler http.Handler, middleware ...MiddlewareFn[RouteState]) error {
+	path := joinRoutePath(prefix, "*path")
+	fn := HttpHandler[RouteState](handler)
+	return self.Update(func(staging *RouteCollection[RouteState]) error {
+		for _, method := range HttpMethods {
+			if method == None || method == Options {
+				continue
+			}
+			err := staging.AddRouteHandler(method, path, RouteHandler[RouteState]{
+				Handler:    fn,
+				Middleware: middleware,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// toStd builds a net/http request equivalent to this request for use by adapted handlers.
+func (req *HttpRequest) toStd(ctx context.Context) (*http.Request, error) {
+	target := req.RawPath
+	if target == "" {
+		target = req.Path
+	}
+	if req.QueryString != "" {
+		target += "?" + req.QueryString
+	}
+	r, err := http.NewRequestWithContext(ctx, string(req.Method), target, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range req.Headers {
+		for _, value := range values {
+			r.Header.Add(key, value)
+		}
+	}
+	r.Host = req.Headers.Get("Host")
+	r.RemoteAddr = req.IpAddress
+	r.RequestURI = target
+	return r, nil
+}
+
+// responseRecorder is the http.ResponseWriter given to adapted net/http handlers.
+// It buffers the status, headers and body so they can be turned into an HttpResponse.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (self *responseRecorder) Header() http.Header {
+	return self.header
+}
+
+func (self *responseRecorder) WriteHeader(status int) {
+	if self.status == 0 {
+		self.status = status
+	}
+}
+
+func (self *responseRecorder) Write(data []byte) (int, error) {
+	self.WriteHeader(http.StatusOK)
+	return self.body.Write(data)
+}
+
+// response converts the recorded output into an HttpResponse. Content-Length is dropped
+// because HttpResponse.Write computes it from the body.
+func (self *responseRecorder) response() *HttpResponse {
+	res := NewHttpResponse()
+	res.StatusCode = StatusCode(self.status)
+	if self.status == 0 {
+		res.StatusCode = StatusOK
+	}
+	for key, values := range self.header {
+		if key != "Content-Length" {
+			res.Headers[key] = append([]string(nil), values...)
+		}
+	}
+	res.Body = self.body.Bytes()
+	return res
+}
+
+// flushWriter flushes a net/http ResponseWriter after every write, so streamed data is sent
+// to the client as it is produced instead of when net/http's buffer fills.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (self flushWriter) Write(data []byte) (int, error) {
+	n, err := self.w.Write(data)
+	if flusher, ok := self.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
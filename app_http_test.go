@@ -0,0 +1,118 @@
+package pilot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHTTP(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Post, "/users/:id", func(req *RouteRequest[struct{}]) *HttpResponse {
+		response := StringResponse(req.Request.GetParam("id") + ":" + string(req.Request.Body) + ":" + req.Request.Headers.Get("X-Token"))
+		response.SetHeader("X-Handled", "true")
+		return response
+	})
+	app.Host("api.example.com").AddRoute(Get, "/", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse("api")
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	request, _ := http.NewRequest("POST", server.URL+"/users/42?x=1", strings.NewReader("hello"))
+	request.Header.Set("X-Token", "secret")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != 200 || string(body) != "42:hello:secret" {
+		t.Errorf("POST /users/42 = %d %q", response.StatusCode, body)
+	}
+	if response.Header.Get("X-Handled") != "true" || response.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("response headers = %v", response.Header)
+	}
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest("GET", "http://api.example.com/", nil))
+	if recorder.Code != 200 || recorder.Body.String() != "api" {
+		t.Errorf("GET api.example.com/ = %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/42", nil))
+	if recorder.Code != 405 || recorder.Header().Get("Allow") != "OPTIONS, POST" {
+		t.Errorf("GET /users/42 = %d Allow %q", recorder.Code, recorder.Header().Get("Allow"))
+	}
+}
+
+func TestMount(t *testing.T) {
+	app := newTestApplication()
+	calls := 0
+	authorize := func(req *RouteRequest[struct{}]) *HttpResponse {
+		calls++
+		if req.Request.Headers.Get("Authorization") == "" {
+			response := StringResponse("missing token")
+			response.SetStatus(StatusForbidden)
+			return response
+		}
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Std", "true")
+		w.WriteHeader(http.StatusTeapot)
+		io.WriteString(w, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+	})
+	if err := app.Routes.Mount("/debug/pprof", mux, authorize); err != nil {
+		t.Fatal(err)
+	}
+	app.Routes.AddRoute(Get, "/debug/pprof/status", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse("pilot")
+	})
+
+	tests := []struct {
+		method  HttpMethod
+		path    string
+		auth    string
+		status  StatusCode
+		body    string
+		handled bool
+	}{
+		{Get, "/debug/pprof/heap", "token", 418, "GET /debug/pprof/heap?debug=1", true},
+		{Post, "/debug/pprof/profile/cpu", "token", 418, "POST /debug/pprof/profile/cpu?debug=1", true},
+		{Get, "/debug/pprof/heap", "", StatusForbidden, "missing token", false},
+		{Get, "/debug/pprof/status", "", StatusOK, "pilot", false},
+	}
+	for _, test := range tests {
+		request := &HttpRequest{
+			Method:      test.method,
+			Path:        test.path,
+			QueryString: "debug=1",
+			Headers:     Header{"Authorization": {test.auth}},
+		}
+		response := app.serveRequest(context.Background(), request, func(string) {})
+		if response.StatusCode != test.status || string(response.Body) != test.body {
+			t.Errorf("%s %s = %d %q, want %d %q", test.method, test.path, response.StatusCode, response.Body, test.status, test.body)
+		}
+		if handled := response.Headers.Get("X-Std") == "true"; handled != test.handled {
+			t.Errorf("%s %s reached the mounted handler = %v", test.method, test.path, handled)
+		}
+		if response.Headers.Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("%s %s missing CORS headers: %v", test.method, test.path, response.Headers)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("middleware ran %d times, want 3", calls)
+	}
+
+	response := serveTestRequest(app, Options, "/debug/pprof/heap")
+	if response.StatusCode != StatusOK || response.Headers.Get("X-Std") != "" {
+		t.Errorf("OPTIONS = %d %v, want an automatic preflight response", response.StatusCode, response.Headers)
+	}
+}
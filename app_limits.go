@@ -0,0 +1,147 @@
+package pilot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RequestLimits bounds how much data a single request may make the server read and buffer.
+// Requests exceeding a limit are rejected without reading the rest of them, and the
+// connection is closed after the response:
+//   - A request line longer than MaxRequestLineBytes is answered with 414 URI Too Long
+//   - More than MaxHeaderCount header fields, or header lines totalling more than
+//     MaxHeaderBytes, are answered with 431 Request Header Fields Too Large
+//   - A body longer than MaxBodyBytes is answered with 413 Content Too Large; a declared
+//     Content-Length is checked before any of the body is read or allocated
+//
+// Byte counts include the line terminators. A zero field in Application.Limits means no
+// limit. In RouteHandler.Limits, a zero field keeps the application's value and a negative
+// one removes the limit for that route.
+//
+// The request line and headers are read before the route is known, so the application's
+// limits always apply to them and a route can only make them stricter. The body is read
+// once the route has been matched, so a route can raise or lower MaxBodyBytes. When the
+// application is used through ServeHTTP, the request line and headers are also subject
+// to the limits of the http.Server, see http.Server.MaxHeaderBytes.
+//
+// Fields:
+//   - MaxRequestLineBytes: Maximum length of the request line (default: 8 KiB)
+//   - MaxHeaderCount: Maximum number of header fields (default: 100)
+//   - MaxHeaderBytes: Maximum combined length of all header lines (default: 64 KiB);
+//     also bounds each chunk size line of a chunked body, a longer one being answered
+//     with 400 Bad Request, and its trailer section, answered with 413 when exceeded
+//   - MaxBodyBytes: Maximum length of the decoded body (default: 10 MiB)
+//
+// Example:
+//
+//	app.Limits.MaxBodyBytes = 1 << 20
+//	app.Routes.AddRouteHandler(pilot.Post, "/uploads", pilot.RouteHandler[AppState]{
+//	    Handler: upload,
+//	    Limits:  pilot.RequestLimits{MaxBodyBytes: 512 << 20},
+//	})
+type RequestLimits struct {
+	MaxRequestLineBytes int
+	MaxHeaderCount      int
+	MaxHeaderBytes      int
+	MaxBodyBytes        int64
+}
+
+// defaultRequestLimits are the limits new applications start with.
+var defaultRequestLimits = RequestLimits{
+	MaxRequestLineBytes: 8 << 10,
+	MaxHeaderCount:      100,
+	MaxHeaderBytes:      64 << 10,
+	MaxBodyBytes:        10 << 20,
+}
+
+// ErrRequestTooLarge is returned (wrapped) by request parsing when a request exceeds one
+// of the configured RequestLimits.
+var ErrRequestTooLarge = errors.New("pilot: request too large")
+
+// limitError reports which limit a request exceeded and the status it is answered with.
+type limitError struct {
+	status StatusCode
+	detail string
+}
+
+func (self *limitError) Error() string {
+	return ErrRequestTooLarge.Error() + ": " + self.detail
+}
+
+func (self *limitError) Unwrap() error {
+	return ErrRequestTooLarge
+}
+
+// tooLarge returns an error for a request exceeding a limit, answered with status.
+func tooLarge(status StatusCode, format string, args ...any) error {
+	return &limitError{status: status, detail: fmt.Sprintf(format, args...)}
+}
+
+// override returns the limits for a route: every non-zero field of route replaces the
+// corresponding field of self, with negative values meaning no limit.
+func (self RequestLimits) override(route RequestLimits) RequestLimits {
+	if route.MaxRequestLineBytes != 0 {
+		self.MaxRequestLineBytes = route.MaxRequestLineBytes
+	}
+	if route.MaxHeaderCount != 0 {
+		self.MaxHeaderCount = route.MaxHeaderCount
+	}
+	if route.MaxHeaderBytes != 0 {
+		self.MaxHeaderBytes = route.MaxHeaderBytes
+	}
+	if route.MaxBodyBytes != 0 {
+		self.MaxBodyBytes = route.MaxBodyBytes
+	}
+	return self
+}
+
+// checkHead reports whether the request line and headers of an already parsed request
+// exceed the limits, which is how a route applies limits stricter than the application's.
+func (self RequestLimits) checkHead(request *HttpRequest) error {
+	if exceeds(int64(self.MaxRequestLineBytes), int64(request.lineBytes)) {
+		return tooLarge(StatusURITooLong, "request line of %d bytes", request.lineBytes)
+	}
+	if exceeds(int64(self.MaxHeaderCount), int64(request.headerCount)) {
+		return tooLarge(StatusRequestHeaderFieldsTooLarge, "%d header fields", request.headerCount)
+	}
+	if exceeds(int64(self.MaxHeaderBytes), int64(request.headerBytes)) {
+		return tooLarge(StatusRequestHeaderFieldsTooLarge, "%d bytes of header fields", request.headerBytes)
+	}
+	return nil
+}
+
+// exceeds reports whether n is over limit, where a limit of zero or less means none.
+func exceeds(limit int64, n int64) bool {
+	return limit > 0 && n > limit
+}
+
+// readLine reads one line including its terminator, failing with tooLong instead of
+// buffering more than limit bytes. A limit of zero or less means no limit. On a read
+// error, the part of the line read before it is returned along with the error.
+func readLine(reader *bufio.Reader, limit int, tooLong func() error) (string, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if exceeds(int64(limit), int64(len(line)+len(chunk))) {
+			return "", tooLong()
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return string(line), err
+		}
+		return string(line), nil
+	}
+}
+
+// serveLimitExceeded builds the response for a request that exceeded one of the limits.
+func (a *Application[RouteState]) serveLimitExceeded(err *limitError) *HttpResponse {
+	response := StringResponse(fmt.Sprintf("%d %s", err.status, strings.ToLower(err.status.Reason())))
+	response.SetStatus(err.status)
+	response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	return response
+}
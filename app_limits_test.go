@@ -0,0 +1,122 @@
+package pilot
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadRequestLimits(t *testing.T) {
+	limits := RequestLimits{MaxRequestLineBytes: 64, MaxHeaderCount: 3, MaxHeaderBytes: 80, MaxBodyBytes: 10}
+	tests := []struct {
+		name   string
+		raw    string
+		status StatusCode
+	}{
+		{"request line", "GET /" + strings.Repeat("a", 64) + " HTTP/1.1\r\n\r\n", StatusURITooLong},
+		{"header count", "GET / HTTP/1.1\r\nA: 1\r\nB: 2\r\nC: 3\r\nD: 4\r\n\r\n", StatusRequestHeaderFieldsTooLarge},
+		{"header bytes", "GET / HTTP/1.1\r\nCookie: " + strings.Repeat("c", 80) + "\r\n\r\n", StatusRequestHeaderFieldsTooLarge},
+		{"content length", "POST / HTTP/1.1\r\nContent-Length: 1000000000000\r\n\r\n", StatusContentTooLarge},
+		{"chunked body", "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n6\r\nhello \r\n6\r\nworld!\r\n0\r\n\r\n", StatusContentTooLarge},
+		{"trailers", "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n1\r\na\r\n0\r\nA: " + strings.Repeat("x", 50) + "\r\nB: " + strings.Repeat("x", 50) + "\r\n\r\n", StatusContentTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := readLimitedTestRequest(t, tt.raw, limits)
+			var limitErr *limitError
+			if !errors.As(err, &limitErr) || !errors.Is(err, ErrRequestTooLarge) {
+				t.Fatalf("err = %v, want ErrRequestTooLarge", err)
+			}
+			if limitErr.status != tt.status {
+				t.Errorf("status = %d, want %d", limitErr.status, tt.status)
+			}
+			if req == nil || req.IpAddress == "" {
+				t.Error("request over the limits did not return the partial request")
+			}
+		})
+	}
+
+	_, err := readLimitedTestRequest(t, "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n1;"+strings.Repeat("x", 100)+"\r\na\r\n0\r\n\r\n", limits)
+	if !errors.Is(err, ErrMalformedRequest) {
+		t.Errorf("chunk size line over the limit err = %v, want ErrMalformedRequest", err)
+	}
+
+	req, err := readLimitedTestRequest(t, "POST / HTTP/1.1\r\nA: 1\r\nB: 2\r\nContent-Length: 10\r\n\r\n0123456789", limits)
+	if err != nil || string(req.Body) != "0123456789" {
+		t.Errorf("request at the limits = %q, %v", req.Body, err)
+	}
+}
+
+func TestRouteLimits(t *testing.T) {
+	newApp := func() *Application[struct{}] {
+		app := newTestApplication()
+		app.Limits.MaxBodyBytes = 8
+		echo := func(req *RouteRequest[struct{}]) *HttpResponse {
+			return StringResponse(string(req.Request.Body))
+		}
+		app.Routes.AddRoute(Post, "/echo", echo)
+		app.Routes.AddRouteHandler(Post, "/upload", RouteHandler[struct{}]{
+			Handler: echo,
+			Limits:  RequestLimits{MaxBodyBytes: 32},
+		})
+		app.Routes.AddRouteHandler(Post, "/strict", RouteHandler[struct{}]{
+			Handler: echo,
+			Limits:  RequestLimits{MaxHeaderCount: 1},
+		})
+		return app
+	}
+	body := "0123456789abcdef"
+
+	t.Run("application limit", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		go io.WriteString(client, "POST /echo HTTP/1.1\r\nContent-Length: 16\r\n\r\n"+body)
+		response := readTestResponse(t, reader)
+		if response.StatusCode != int(StatusContentTooLarge) || !response.Close {
+			t.Errorf("POST /echo = %d, close %v", response.StatusCode, response.Close)
+		}
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("raised by route", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "POST /upload HTTP/1.1\r\nContent-Length: 16\r\n\r\n"+body)
+		if response := readTestResponse(t, reader); response.StatusCode != 200 || response.Close {
+			t.Errorf("POST /upload = %d, close %v", response.StatusCode, response.Close)
+		}
+	})
+
+	t.Run("unread body", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "POST /missing HTTP/1.1\r\nContent-Length: 5\r\n\r\nhelloPOST /echo HTTP/1.1\r\nContent-Length: 2\r\n\r\nok")
+		if response := readTestResponse(t, reader); response.StatusCode != int(StatusNotFound) || response.Close {
+			t.Errorf("POST /missing = %d, close %v", response.StatusCode, response.Close)
+		}
+		if response := readTestResponse(t, reader); response.StatusCode != 200 {
+			t.Errorf("request after an unread body = %d", response.StatusCode)
+		}
+	})
+
+	t.Run("stricter headers", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		go io.WriteString(client, "POST /strict HTTP/1.1\r\nHost: a\r\nContent-Length: 2\r\n\r\nok")
+		if response := readTestResponse(t, reader); response.StatusCode != int(StatusRequestHeaderFieldsTooLarge) {
+			t.Errorf("POST /strict = %d", response.StatusCode)
+		}
+	})
+
+	t.Run("serve http", func(t *testing.T) {
+		app := newApp()
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest("POST", "/echo", strings.NewReader(body)))
+		if recorder.Code != int(StatusContentTooLarge) {
+			t.Errorf("POST /echo = %d", recorder.Code)
+		}
+		recorder = httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest("POST", "/upload", strings.NewReader(body)))
+		if recorder.Code != 200 || recorder.Body.String() != body {
+			t.Errorf("POST /upload = %d %q", recorder.Code, recorder.Body)
+		}
+	})
+}
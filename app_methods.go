@@ -1,5 +1,10 @@
 package pilot
 
+import (
+	"strconv"
+	"strings"
+)
+
 // HttpMethod represents the HTTP request method/verb used for routing and handler dispatch.
 // The framework supports all standard HTTP methods with type safety to prevent routing errors.
 type HttpMethod string
@@ -16,6 +21,7 @@ type RequestHandler[RouteState any] func(RouteState, *HttpRequest) *HttpResponse
 //
 // Supported methods:
 //   - Get: Retrieve data, should be idempotent and safe
+//   - Head: Same as Get without a response body, answered by the Get handler unless one is registered
 //   - Post: Create new resources, non-idempotent
 //   - Put: Update/replace entire resources, idempotent
 //   - Patch: Partial resource updates, may or may not be idempotent
@@ -24,6 +30,7 @@ type RequestHandler[RouteState any] func(RouteState, *HttpRequest) *HttpResponse
 //   - None: Internal placeholder, not used for actual routing
 const (
 	Get     HttpMethod = "GET"
+	Head    HttpMethod = "HEAD"
 	Post    HttpMethod = "POST"
 	Put     HttpMethod = "PUT"
 	Patch   HttpMethod = "PATCH"
@@ -38,10 +45,13 @@ const (
 // type-safe routing and handler dispatch.
 //
 // The map includes all standard HTTP methods and an internal "NONE" value
-// used as a placeholder for invalid or unrecognized methods.
+// used as a placeholder for invalid or unrecognized methods. Extension methods
+// are added with RegisterMethod; requests using a method that is not in the map
+// are answered with 501 Not Implemented.
 var (
 	HttpMethods = map[string]HttpMethod{
 		"GET":     Get,
+		"HEAD":    Head,
 		"POST":    Post,
 		"PUT":     Put,
 		"PATCH":   Patch,
@@ -50,3 +60,64 @@ var (
 		"NONE":    None,
 	}
 )
+
+// RegisterMethod adds an extension method token, such as a WebDAV or cache invalidation
+// method, to HttpMethods so that routes can be registered for it and requests using it
+// are dispatched instead of being answered with 501 Not Implemented. Method tokens are
+// case-sensitive. Registering a method that already exists returns the existing value.
+//
+// Methods must be registered before routes using them are added and before the application
+// starts serving requests, typically in a package-level variable declaration. It panics if
+// token is not a valid HTTP method token, since method names are declared by the application
+// and an invalid one is a programming error.
+//
+// Parameters:
+//   - token: The method name as it appears in the request line (e.g., "PROPFIND")
+//
+// Returns:
+//   - HttpMethod: The method value to register routes with
+//
+// Example:
+//
+//	var (
+//	    Propfind = pilot.RegisterMethod("PROPFIND")
+//	    Purge    = pilot.RegisterMethod("PURGE")
+//	)
+//
+//	app.Routes.AddRoute(Propfind, "/dav/*path", listProperties)
+//	app.Routes.AddRoute(Purge, "/cache/*key", purgeCache)
+func RegisterMethod(token string) HttpMethod {
+	if !isMethodToken(token) {
+		panic("pilot: invalid HTTP method token " + strconv.Quote(token))
+	}
+	if method, found := HttpMethods[token]; found {
+		return method
+	}
+	method := HttpMethod(token)
+	HttpMethods[token] = method
+	return method
+}
+
+// isImplemented reports whether requests using the method can be dispatched to routes,
+// i.e. whether it is a standard or registered method other than the None placeholder.
+func (m HttpMethod) isImplemented() bool {
+	return m != None && HttpMethods[string(m)] == m
+}
+
+// isMethodToken reports whether s is a valid method token as defined by RFC 9110:
+// one or more letters, digits or the characters !#$%&'*+-.^_`|~
+func isMethodToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
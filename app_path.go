@@ -0,0 +1,153 @@
+package pilot
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// TrailingSlashPolicy controls how a request is handled when its path ends with a slash
+// and the matched route was registered without one, or the other way around.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashIgnore serves "/users" and "/users/" with the same route.
+	TrailingSlashIgnore TrailingSlashPolicy = iota
+	// TrailingSlashRedirect redirects to the registered form of the path: 301 Moved
+	// Permanently for GET and HEAD, 308 Permanent Redirect for other methods so the
+	// method and body are preserved.
+	TrailingSlashRedirect
+	// TrailingSlashStrict treats a mismatched trailing slash as an unknown route.
+	TrailingSlashStrict
+)
+
+// normalizePath rewrites request.Path into the form used for routing according to the
+// DecodePath and CleanPath settings, keeping the original in request.RawPath.
+//
+// The path is split into segments before they are decoded, so an encoded slash ("%2F")
+// stays part of its segment instead of becoming a separator, and is kept encoded in
+// request.Path (see escapeSegment). Cleaning runs on the decoded segments, so encoded dot
+// segments ("%2e%2e") are resolved like their literal forms. A segment with an encoded
+// slash is rejected as malformed if the slash makes it contain an empty, "." or ".."
+// part ("..%2Fsecret", "%2Fetc"), so no parameter can carry a path that climbs out of
+// its directory or is absolute.
+func (a *Application[RouteState]) normalizePath(request *HttpRequest) error {
+	if request.RawPath == "" {
+		request.RawPath = request.Path
+	}
+	if a.DecodePath && strings.IndexByte(request.Path, '%') >= 0 {
+		segments := strings.Split(request.Path, "/")
+		for i, segment := range segments {
+			decoded, err := url.PathUnescape(segment)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrMalformedRequest, err)
+			}
+			if !isCleanSegment(decoded) {
+				return fmt.Errorf("%w: encoded slash in path segment %q", ErrMalformedRequest, segment)
+			}
+			segments[i] = escapeSegment(decoded)
+		}
+		request.Path = strings.Join(segments, "/")
+	}
+	if a.CleanPath {
+		request.Path = cleanPath(request.Path)
+	}
+	return nil
+}
+
+// isCleanSegment reports whether the parts of a decoded path segment that contains encoded
+// slashes are all regular names, none of them empty, "." or "..".
+func isCleanSegment(decoded string) bool {
+	if strings.IndexByte(decoded, '/') < 0 {
+		return true
+	}
+	for _, part := range strings.Split(decoded, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentEscaper re-encodes the characters that would make a decoded segment ambiguous.
+var segmentEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// escapeSegment re-encodes "%" and "/" in a decoded path segment, so that joining the
+// segments back into a path neither adds separators nor lets a literal "%2F" be mistaken
+// for an encoded slash. These are the only escapes left in a decoded request.Path.
+func escapeSegment(segment string) string {
+	if strings.ContainsAny(segment, "%/") {
+		return segmentEscaper.Replace(segment)
+	}
+	return segment
+}
+
+// unescapeParams decodes the escapes left by escapeSegment in matched parameter values,
+// so handlers receive them fully decoded, with an encoded slash as "/".
+func unescapeParams(params PathParams) {
+	for i := range params {
+		if strings.IndexByte(params[i].Value, '%') >= 0 {
+			if value, err := url.PathUnescape(params[i].Value); err == nil {
+				params[i].Value = value
+			}
+		}
+	}
+}
+
+// cleanPath resolves ".", ".." and repeated slashes in a request path like path.Clean,
+// without ever climbing above the root, and preserves a trailing slash so that the
+// trailing slash policy still sees it.
+//
+// Examples:
+//   - "/a//b/./c/" → "/a/b/c/"
+//   - "/a/../../etc/passwd" → "/etc/passwd"
+//   - "" → "/"
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	cleaned := path.Clean(p)
+	if cleaned != "/" && hasTrailingSlash(p) {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// hasTrailingSlash reports whether a path other than the root ends with "/".
+func hasTrailingSlash(p string) bool {
+	return len(p) > 1 && p[len(p)-1] == '/'
+}
+
+// trailingSlashRedirect builds the redirect to the registered form of the request path,
+// re-encoding the path segment by segment and keeping the query string. Leading slashes
+// are collapsed into one even when CleanPath is off, since a Location starting with "//"
+// is a protocol-relative URL that would send the client to another host.
+func (a *Application[RouteState]) trailingSlashRedirect(request *HttpRequest, trailingSlash bool) *HttpResponse {
+	target := "/" + strings.TrimLeft(strings.TrimSuffix(request.Path, "/"), "/")
+	if trailingSlash {
+		target += "/"
+	}
+	segments := strings.Split(target, "/")
+	for i, segment := range segments {
+		if a.DecodePath {
+			segment, _ = url.PathUnescape(segment)
+		}
+		segments[i] = url.PathEscape(segment)
+	}
+	location := strings.Join(segments, "/")
+	if request.QueryString != "" {
+		location += "?" + request.QueryString
+	}
+	response := StringResponse("")
+	response.SetStatus(StatusPermanentRedirect)
+	if request.Method == Get || request.Method == Head {
+		response.SetStatus(StatusMovedPermanently)
+	}
+	response.SetHeader("Location", location)
+	response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	return response
+}
@@ -0,0 +1,166 @@
+package pilot
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := map[string]string{
+		"":                         "/",
+		"/":                        "/",
+		"/a//b/./c/":               "/a/b/c/",
+		"/a/../../etc/passwd":      "/etc/passwd",
+		"/static/../../etc/passwd": "/etc/passwd",
+		"users":                    "/users",
+		"/users/.":                 "/users",
+	}
+	for path, want := range tests {
+		if got := cleanPath(path); got != want {
+			t.Errorf("cleanPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestPathNormalization(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/files/*path", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse(req.Request.GetParam("path"))
+	})
+	app.Routes.AddRoute(Get, "/users/:name", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse(req.Request.GetParam("name"))
+	})
+	app.Routes.AddRoute(Get, "/café", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse("menu")
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/files/a//b/./c.txt", want: "a/b/c.txt"},
+		{path: "/files/../files/x", want: "x"},
+		{path: "/files/%2e%2e/%2e%2e/etc/passwd", want: "404"},
+		{path: "/files/a%2F..%2Fsecret", want: "400"},
+		{path: "/files/%2e%2e%2fsecret", want: "400"},
+		{path: "/files/..%2F..%2Fetc%2Fpasswd", want: "400"},
+		{path: "/files/%2Fetc%2Fpasswd", want: "400"},
+		{path: "/files/a%2F%2Fb", want: "400"},
+		{path: "/files/a%2Fb/c", want: "a/b/c"},
+		{path: "/files/a%252Fb", want: "a%2Fb"},
+		{path: "/users/a%2Fb", want: "a/b"},
+		{path: "/users/a%2Fb/c", want: "404"},
+		{path: "/files/report%201.pdf", want: "report 1.pdf"},
+		{path: "/caf%C3%A9", want: "menu"},
+		{path: "/files/%zz", want: "400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			response := serveTestRequest(app, Get, tt.path)
+			got := string(response.Body)
+			if response.StatusCode != StatusOK {
+				got = string(response.Body[:3])
+			}
+			if got != tt.want {
+				t.Errorf("GET %s = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest("GET", "/files/..%2F..%2Fetc%2Fpasswd", nil))
+	if recorder.Code != int(StatusBadRequest) {
+		t.Errorf("ServeHTTP encoded traversal = %d %q, want 400", recorder.Code, recorder.Body)
+	}
+
+	app.DecodePath = false
+	app.CleanPath = false
+	response := serveTestRequest(app, Get, "/files/a//b%20c")
+	if string(response.Body) != "a//b%20c" {
+		t.Errorf("raw path served as %q", response.Body)
+	}
+}
+
+func TestTrailingSlashPolicy(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/users", noopHandler)
+	app.Routes.AddRoute(Post, "/users", noopHandler)
+	app.Routes.AddRoute(Get, "/docs/", noopHandler)
+
+	if response := serveTestRequest(app, Get, "/users/"); response.StatusCode != StatusOK {
+		t.Errorf("TrailingSlashIgnore status = %d", response.StatusCode)
+	}
+
+	app.TrailingSlash = TrailingSlashRedirect
+	request := &HttpRequest{Method: Get, Path: "/users/", QueryString: "page=2", Headers: Header{}}
+	response := app.serveRequest(context.Background(), request, func(string) {})
+	if response.StatusCode != StatusMovedPermanently || response.Headers.Get("Location") != "/users?page=2" {
+		t.Errorf("GET redirect = %d %q", response.StatusCode, response.Headers.Get("Location"))
+	}
+	response = serveTestRequest(app, Post, "/users/")
+	if response.StatusCode != StatusPermanentRedirect || response.Headers.Get("Location") != "/users" {
+		t.Errorf("POST redirect = %d %q", response.StatusCode, response.Headers.Get("Location"))
+	}
+	response = serveTestRequest(app, Get, "/docs")
+	if response.StatusCode != StatusMovedPermanently || response.Headers.Get("Location") != "/docs/" {
+		t.Errorf("GET /docs redirect = %d %q", response.StatusCode, response.Headers.Get("Location"))
+	}
+	app.Routes.AddRoute(Get, "/files/:name", noopHandler)
+	response = serveTestRequest(app, Get, "/files/a%2Fb%20c/")
+	if response.StatusCode != StatusMovedPermanently || response.Headers.Get("Location") != "/files/a%2Fb%20c" {
+		t.Errorf("GET encoded slash redirect = %d %q", response.StatusCode, response.Headers.Get("Location"))
+	}
+	if response := serveTestRequest(app, Get, "/users"); response.StatusCode != StatusOK {
+		t.Errorf("canonical path status = %d", response.StatusCode)
+	}
+
+	app.TrailingSlash = TrailingSlashStrict
+	if response := serveTestRequest(app, Get, "/users/"); response.StatusCode != StatusNotFound {
+		t.Errorf("TrailingSlashStrict status = %d", response.StatusCode)
+	}
+
+	app = newTestApplication()
+	app.CleanPath = false
+	app.TrailingSlash = TrailingSlashRedirect
+	app.Routes.AddRoute(Get, "/*rest", noopHandler)
+	for path, want := range map[string]string{"//evil.com/": "/evil.com", "/%5Cevil.com/": "/%5Cevil.com", "//": "/"} {
+		response := serveTestRequest(app, Get, path)
+		if response.StatusCode != StatusMovedPermanently || response.Headers.Get("Location") != want {
+			t.Errorf("GET %s redirect = %d %q, want %q", path, response.StatusCode, response.Headers.Get("Location"), want)
+		}
+	}
+}
+
+func TestTrailingSlashRegistration(t *testing.T) {
+	app := newTestApplication()
+	app.TrailingSlash = TrailingSlashStrict
+	app.Routes.AddRoute(Get, "/docs/", noopHandler)
+	if err := app.Routes.AddRoute(Post, "/docs", noopHandler); !errors.Is(err, ErrRouteConflict) {
+		t.Errorf("POST /docs next to GET /docs/ = %v, want ErrRouteConflict", err)
+	}
+	if response := serveTestRequest(app, Get, "/docs/"); response.StatusCode != StatusOK {
+		t.Errorf("GET /docs/ = %d", response.StatusCode)
+	}
+	if response := serveTestRequest(app, Get, "/docs"); response.StatusCode != StatusNotFound {
+		t.Errorf("GET /docs = %d, want 404", response.StatusCode)
+	}
+
+	if err := app.Routes.ReplaceRoute(Get, "/docs", RouteHandler[struct{}]{Handler: noopHandler}); err != nil {
+		t.Errorf("ReplaceRoute changing the only handler's form = %v", err)
+	}
+	if response := serveTestRequest(app, Get, "/docs"); response.StatusCode != StatusOK {
+		t.Errorf("GET /docs after ReplaceRoute = %d", response.StatusCode)
+	}
+
+	app.Routes.AddRoute(Get, "/guides/", noopHandler)
+	app.Routes.AddRoute(Get, "/guides/intro", noopHandler)
+	app.Routes.RemoveRoute(Get, "/guides/")
+	if err := app.Routes.AddRoute(Post, "/guides", noopHandler); err != nil {
+		t.Errorf("POST /guides after removing GET /guides/ = %v", err)
+	}
+	if response := serveTestRequest(app, Post, "/guides"); response.StatusCode != StatusOK {
+		t.Errorf("POST /guides = %d", response.StatusCode)
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"bufio"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/url"
 	"strconv"
@@ -18,23 +18,120 @@ import (
 
 // RouteRequest encapsulates all context and resources needed by route handlers.
 // Provides access to HTTP request details, database connection, application context, and typed route state.
+// Metadata is the metadata declared for the matched handler, so middleware can act on it,
+// and Version is the API version of the handler selected for a versioned route.
 type RouteRequest[T any] struct {
 	Request  *HttpRequest
 	Database *sql.DB
 	Context  context.Context
 	State    *T
+	Metadata RouteMetadata
+	Version  string
 }
 
 // HttpRequest represents a parsed HTTP request with convenient access methods.
 // Provides structured access to headers, body content, query parameters, and path components.
+// Subdomain holds the label matched by a wildcard host pattern (see Application.Host).
+// Path is the decoded and cleaned path used for routing, in which only "%" and "/" inside a
+// segment stay encoded (as "%25" and "%2F"), while RawPath keeps the path exactly as the
+// client sent it. Path parameters are fully decoded. Proto is the protocol version from the request line,
+// such as "HTTP/1.1". Headers is looked up case-insensitively and keeps repeated fields
+// (see Header), and Trailers holds the trailer fields sent after a chunked body, if any.
 type HttpRequest struct {
 	Path        string
+	RawPath     string
 	QueryString string
 	Method      HttpMethod
+	Proto       string
 	Body        []byte
-	Headers     map[string]string
+	Headers     Header
+	Trailers    Header
 	IpAddress   string
+	Params      PathParams
+	Subdomain   string
 	_tempMap    *map[string]string
+
+	// Sizes of the request line and header section, checked against route limits.
+	lineBytes   int
+	headerCount int
+	headerBytes int
+	// readBody reads the body once the route and its limits are known; nil once the
+	// body has been read or if the request has none. bodyErr is its error, if any.
+	readBody func(limit int64) ([]byte, Header, error)
+	bodyErr  error
+	// paramBuffer backs Params, so routing a request that captures up to four
+	// parameters does not allocate.
+	paramBuffer [4]PathParam
+}
+
+// PathParam is a single path parameter captured while matching a route pattern,
+// e.g. Name "id" and Value "42" for the pattern "/users/:id" and path "/users/42".
+type PathParam struct {
+	Name  string
+	Value string
+}
+
+// PathParams holds the parameters captured for a request in the order they appear
+// in the route pattern. A slice is used instead of a map because routes rarely
+// capture more than a handful of parameters.
+type PathParams []PathParam
+
+// Get returns the value of the named path parameter and whether it was captured.
+func (params PathParams) Get(name string) (string, bool) {
+	for i := range params {
+		if params[i].Name == name {
+			return params[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// GetParam returns the value of a path parameter captured from the route pattern.
+// Returns an empty string if the route has no parameter with that name.
+func (req *HttpRequest) GetParam(key string) string {
+	val, _ := req.Params.Get(key)
+	return val
+}
+
+// GetParamInt32 extracts a path parameter as a 32-bit signed integer.
+// Returns nil if parameter is missing or cannot be parsed as int32.
+func (req *HttpRequest) GetParamInt32(key string) *int32 {
+	val, ok := req.Params.Get(key)
+	if ok {
+		num, err := strconv.ParseInt(val, 10, 32)
+		if err == nil {
+			v := int32(num)
+			return &v
+		}
+	}
+	return nil
+}
+
+// GetParamInt64 extracts a path parameter as a 64-bit signed integer.
+// Returns nil if parameter is missing or cannot be parsed as int64.
+func (req *HttpRequest) GetParamInt64(key string) *int64 {
+	val, ok := req.Params.Get(key)
+	if ok {
+		num, err := strconv.ParseInt(val, 10, 64)
+		if err == nil {
+			return &num
+		}
+	}
+	return nil
+}
+
+// GetParamUUID extracts and validates a path parameter as a UUID.
+// Returns nil if parameter is missing or not a valid UUID format.
+func (req *HttpRequest) GetParamUUID(key string) *uuid.UUID {
+	val, ok := req.Params.Get(key)
+	if !ok {
+		return nil
+	}
+	g, err := uuid.Parse(val)
+	if err != nil {
+		return nil
+	}
+	return &g
 }
 
 // QueryMap parses the query string into a map of key-value pairs with URL decoding.
@@ -137,76 +234,217 @@ func (req *HttpRequest) Dump() {
 	fmt.Printf("Method: %v\n", req.Method)
 	fmt.Printf("Path: %v\n", req.Path)
 	fmt.Printf("QueryString: %v\n", req.QueryString)
+	for i := range req.Params {
+		fmt.Printf("Param: '%v': '%v'\n", req.Params[i].Name, req.Params[i].Value)
+	}
 	for k, v := range req.Headers {
 		fmt.Printf("Header: '%v': '%v'\n", k, v)
 	}
+	for k, v := range req.Trailers {
+		fmt.Printf("Trailer: '%v': '%v'\n", k, v)
+	}
 	if req.Body != nil {
 		fmt.Printf("Body: %v\n", string(req.Body))
 	}
 }
 
-// ParseRequest reads and parses an HTTP request from a TCP connection.
+// ErrMalformedRequest is returned (wrapped) by request parsing when the client sent data
+// that is not a valid HTTP request, as opposed to the connection closing or timing out.
+var ErrMalformedRequest = errors.New("pilot: malformed request")
+
+// ErrUnsupportedTransferCoding is returned (wrapped) by request parsing when a request body
+// uses a transfer coding other than chunked, such as "gzip, chunked". Such requests are
+// answered with 501 Not Implemented.
+var ErrUnsupportedTransferCoding = errors.New("pilot: unsupported transfer coding")
+
+// ParseRequest reads and parses an HTTP request from a TCP connection, including its body.
 // Implements complete HTTP/1.1 request parser with timeout handling.
+// The default RequestLimits and read timeouts of a new Application apply.
 // Returns nil for malformed requests or connection errors.
 func ParseRequest(incoming *net.Conn) *HttpRequest {
-	(*incoming).SetReadDeadline(time.Now().Add(time.Second * 10))
+	req, err := readRequest(bufio.NewReader(*incoming), *incoming, defaultRequestLimits, defaultReadHeaderTimeout, defaultReadBodyTimeout)
+	if err != nil {
+		return nil
+	}
+	if err := req.loadBody(defaultRequestLimits.MaxBodyBytes); err != nil {
+		return nil
+	}
+	return req
+}
+
+// readRequest reads and parses the request line and headers of a single HTTP request from
+// a buffered connection reader. The body is left unread until loadBody is called with the
+// body limit of the matched route; see RequestLimits.
+//
+// Parameters:
+//   - bufReader: Reader of the connection, positioned at the start of the request
+//   - incoming: The connection, used for the client address and read deadlines
+//   - limits: Size limits for the request line and headers
+//   - headerTimeout: Time allowed for reading the request line and headers; zero for none
+//   - bodyTimeout: Time allowed for reading the body once loadBody is called; zero for none
+//
+// Returns:
+//   - *HttpRequest: The parsed request, or the partially parsed request (at least IpAddress
+//     is set) on any error the client should get a response for, so one can be built
+//   - error: A wrapped ErrMalformedRequest for invalid syntax, a wrapped
+//     ErrUnsupportedTransferCoding, a *limitError wrapping ErrRequestTooLarge when limits
+//     are exceeded, a timeout error (see isTimeout) when
+//     the client started a request but did not finish its headers in time, or the
+//     underlying read error when the connection closed or timed out while idle
+func readRequest(bufReader *bufio.Reader, incoming net.Conn, limits RequestLimits, headerTimeout time.Duration, bodyTimeout time.Duration) (*HttpRequest, error) {
+	incoming.SetReadDeadline(deadline(headerTimeout))
 	req := HttpRequest{
 		Path:        "",
 		Method:      "",
 		Body:        nil,
-		Headers:     make(map[string]string),
+		Headers:     Header{},
 		QueryString: "",
-		IpAddress:   (*incoming).RemoteAddr().String(),
+		IpAddress:   incoming.RemoteAddr().String(),
 	}
 
-	bufReader := bufio.NewReader(*incoming)
-	bytes, err := bufReader.ReadBytes(' ')
+	line, err := readLine(bufReader, limits.MaxRequestLineBytes, func() error {
+		return tooLarge(StatusURITooLong, "request line longer than %d bytes", limits.MaxRequestLineBytes)
+	})
 	if err != nil {
-		return nil
+		if errors.Is(err, ErrRequestTooLarge) || (line != "" && isTimeout(err)) {
+			return &req, err
+		}
+		return nil, err
 	}
-	req.Method = HttpMethods[string(bytes[:len(bytes)-1])]
-	bytes, err = bufReader.ReadBytes(' ')
-	if err != nil {
-		return nil
+	req.lineBytes = len(line)
+	parts := strings.Split(strings.TrimRight(line, "\r\n"), " ")
+	if len(parts) != 3 || parts[1] == "" || !strings.HasPrefix(parts[2], "HTTP/") {
+		return &req, fmt.Errorf("%w: invalid request line %q", ErrMalformedRequest, line)
 	}
-	req.Path = string(bytes[:len(bytes)-1])
-	bytes, err = bufReader.ReadBytes('\n')
-	if err != nil {
-		log.Println(err)
-		return nil
+	method, known := HttpMethods[parts[0]]
+	if !known {
+		if !isMethodToken(parts[0]) {
+			return &req, fmt.Errorf("%w: invalid method %q", ErrMalformedRequest, parts[0])
+		}
+		method = HttpMethod(parts[0])
 	}
+	req.Method = method
+	req.Path = parts[1]
+	req.Proto = parts[2]
 	qryIdx := strings.Index(req.Path, "?")
 	if qryIdx > -1 {
 		req.QueryString = req.Path[qryIdx+1:]
 		req.Path = req.Path[0:qryIdx]
 	}
+	headersTooLarge := func() error {
+		return tooLarge(StatusRequestHeaderFieldsTooLarge, "header fields longer than %d bytes", limits.MaxHeaderBytes)
+	}
 	for {
-		bytes, err = bufReader.ReadBytes('\n')
+		// The limit leaves room for the empty line ending the header section.
+		remaining := 0
+		if limits.MaxHeaderBytes > 0 {
+			remaining = max(limits.MaxHeaderBytes-req.headerBytes, 0) + 2
+		}
+		line, err = readLine(bufReader, remaining, headersTooLarge)
 		if err != nil {
-			return nil
+			if errors.Is(err, ErrRequestTooLarge) || isTimeout(err) {
+				return &req, err
+			}
+			return nil, err
 		}
-		if bytes[0] == '\r' {
+		if line[0] == '\r' {
 			break
 		}
-		if bytes[0] == '\n' {
+		if line[0] == '\n' {
 			continue
 		}
-		header := string(bytes)
-		header = header[0 : len(header)-2]
-		split := strings.Split(header, ":")
-		req.Headers[split[0]] = split[1][1:]
+		req.headerBytes += len(line)
+		req.headerCount++
+		if exceeds(int64(limits.MaxHeaderBytes), int64(req.headerBytes)) {
+			return &req, headersTooLarge()
+		}
+		if exceeds(int64(limits.MaxHeaderCount), int64(req.headerCount)) {
+			return &req, tooLarge(StatusRequestHeaderFieldsTooLarge, "more than %d header fields", limits.MaxHeaderCount)
+		}
+		header := strings.TrimRight(line, "\r\n")
+		name, value, found := strings.Cut(header, ":")
+		if !found || !isFieldName(name) {
+			return &req, fmt.Errorf("%w: invalid header line %q", ErrMalformedRequest, header)
+		}
+		req.Headers.Add(name, strings.Trim(value, " \t"))
+	}
+	if len(req.Headers.Values("Host")) > 1 {
+		return &req, fmt.Errorf("%w: multiple Host headers", ErrMalformedRequest)
 	}
 
-	// Read body
-	if req.Headers["Content-Length"] != "" {
-		bodyLength, _ := strconv.Atoi(req.Headers["Content-Length"])
-		body := make([]byte, bodyLength)
-		_, err = io.ReadFull(bufReader, body)
-		if err != nil {
-			return nil
+	// A request framed by both Transfer-Encoding and Content-Length is rejected, since
+	// intermediaries may disagree about where it ends (request smuggling).
+	contentLength, err := singleContentLength(req.Headers.Values("Content-Length"))
+	if err != nil {
+		return &req, err
+	}
+	transferEncoding := req.Headers.joined("Transfer-Encoding")
+	if transferEncoding != "" {
+		if contentLength >= 0 {
+			return &req, fmt.Errorf("%w: both Transfer-Encoding and Content-Length are set", ErrMalformedRequest)
+		}
+		if err := checkTransferEncoding(transferEncoding); err != nil {
+			return &req, err
+		}
+		req.readBody = func(limit int64) ([]byte, Header, error) {
+			incoming.SetReadDeadline(deadline(bodyTimeout))
+			return readChunked(bufReader, limit, limits.MaxHeaderBytes)
+		}
+	} else if contentLength >= 0 {
+		req.readBody = func(limit int64) ([]byte, Header, error) {
+			if exceeds(limit, contentLength) {
+				return nil, nil, tooLarge(StatusContentTooLarge, "Content-Length %d exceeds %d bytes", contentLength, limit)
+			}
+			incoming.SetReadDeadline(deadline(bodyTimeout))
+			body := make([]byte, contentLength)
+			if _, err := io.ReadFull(bufReader, body); err != nil {
+				return nil, nil, err
+			}
+			return body, nil, nil
 		}
-		req.Body = body
 	}
 
-	return &req
+	return &req, nil
+}
+
+// loadBody reads the body of the request if it has not been read yet, failing with a
+// *limitError instead of reading more than limit bytes; a limit of zero or less means no
+// limit. After an error the connection is no longer usable, which bodyErr records.
+func (req *HttpRequest) loadBody(limit int64) error {
+	if req.readBody == nil {
+		return nil
+	}
+	read := req.readBody
+	req.readBody = nil
+	body, trailers, err := read(limit)
+	if err != nil {
+		req.bodyErr = err
+		return err
+	}
+	req.Body = body
+	if trailers != nil {
+		req.Trailers = trailers
+	}
+	return nil
+}
+
+// singleContentLength parses the Content-Length values of a request. Repeated fields or
+// comma-separated lists are accepted only if every value is the same, as a mismatch would
+// leave the body length ambiguous.
+//
+// Returns:
+//   - int64: The body length, or -1 if Content-Length is not set
+//   - error: A wrapped ErrMalformedRequest for invalid or conflicting values
+func singleContentLength(values []string) (int64, error) {
+	var length int64 = -1
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil || n < 0 || (length >= 0 && n != length) {
+				return 0, fmt.Errorf("%w: invalid Content-Length %q", ErrMalformedRequest, strings.Join(values, ", "))
+			}
+			length = n
+		}
+	}
+	return length, nil
 }
@@ -0,0 +1,134 @@
+package pilot
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"testing"
+)
+
+func readTestRequest(t *testing.T, raw string) (*HttpRequest, error) {
+	t.Helper()
+	return readLimitedTestRequest(t, raw, defaultRequestLimits)
+}
+
+func readLimitedTestRequest(t *testing.T, raw string, limits RequestLimits) (*HttpRequest, error) {
+	t.Helper()
+	server, client := net.Pipe()
+	defer server.Close()
+	go func() {
+		client.Write([]byte(raw))
+		client.Close()
+	}()
+	req, err := readRequest(bufio.NewReader(server), server, limits, defaultReadHeaderTimeout, defaultReadBodyTimeout)
+	if err == nil {
+		err = req.loadBody(limits.MaxBodyBytes)
+	}
+	return req, err
+}
+
+func TestReadRequest(t *testing.T) {
+	req, err := readTestRequest(t, "POST /users?active=1 HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5\r\n\r\nhello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != Post || req.Path != "/users" || req.QueryString != "active=1" {
+		t.Errorf("request line parsed as %s %s ? %s", req.Method, req.Path, req.QueryString)
+	}
+	if req.Headers.Get("Host") != "example.com" {
+		t.Errorf("Host = %q", req.Headers.Get("Host"))
+	}
+	if string(req.Body) != "hello" {
+		t.Errorf("Body = %q", req.Body)
+	}
+}
+
+func TestReadRequestMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "request line", raw: "GET /\r\n\r\n"},
+		{name: "protocol", raw: "GET / FTP/1.0\r\n\r\n"},
+		{name: "header", raw: "GET / HTTP/1.1\r\nno-colon\r\n\r\n"},
+		{name: "content length", raw: "POST / HTTP/1.1\r\nContent-Length: ten\r\n\r\n"},
+		{name: "conflicting content lengths", raw: "POST / HTTP/1.1\r\nContent-Length: 5\r\ncontent-length: 6\r\n\r\nhello!"},
+		{name: "space before colon", raw: "GET / HTTP/1.1\r\nHost : example.com\r\n\r\n"},
+		{name: "multiple hosts", raw: "GET / HTTP/1.1\r\nHost: a.example.com\r\nHost: b.example.com\r\n\r\n"},
+		{name: "method", raw: "GE(T / HTTP/1.1\r\n\r\n"},
+		{name: "chunked and content length", raw: "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\nContent-Length: 5\r\n\r\n5\r\nhello\r\n0\r\n\r\n"},
+		{name: "chunked and content length case", raw: "POST / HTTP/1.1\r\ncontent-length: 5\r\ntransfer-encoding: chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\n"},
+		{name: "chunked not final", raw: "POST / HTTP/1.1\r\nTransfer-Encoding: chunked, gzip\r\n\r\n"},
+		{name: "chunk size", raw: "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\nzz\r\nhello\r\n0\r\n\r\n"},
+		{name: "chunk length", raw: "POST / HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nhello\r\n0\r\n\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := readTestRequest(t, tt.raw)
+			if !errors.Is(err, ErrMalformedRequest) {
+				t.Fatalf("err = %v, want ErrMalformedRequest", err)
+			}
+			if req == nil || req.IpAddress == "" {
+				t.Error("malformed request did not return the partial request")
+			}
+		})
+	}
+
+	if _, err := readTestRequest(t, "GET / HTTP/1.1\r\nHost: exa"); err == nil || errors.Is(err, ErrMalformedRequest) {
+		t.Errorf("truncated request err = %v, want read error", err)
+	}
+	if _, err := readTestRequest(t, "POST / HTTP/1.1\r\nTransfer-Encoding: gzip, chunked\r\n\r\n"); !errors.Is(err, ErrUnsupportedTransferCoding) {
+		t.Errorf("gzip transfer coding err = %v, want ErrUnsupportedTransferCoding", err)
+	}
+}
+
+func TestReadRequestHeaders(t *testing.T) {
+	req, err := readTestRequest(t, "POST / HTTP/1.1\r\n"+
+		"Host: example.com:8080\r\n"+
+		"content-length: 5\r\n"+
+		"Content-Length: 5\r\n"+
+		"X-Forwarded-For: 10.0.0.1\r\n"+
+		"x-forwarded-for:10.0.0.2 \r\n"+
+		"Referer: https://example.com/a?b=c:d\r\n\r\nhello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host := req.Headers.Get("host"); host != "example.com:8080" {
+		t.Errorf("Host = %q, want example.com:8080", host)
+	}
+	if referer := req.Headers.Get("Referer"); referer != "https://example.com/a?b=c:d" {
+		t.Errorf("Referer = %q", referer)
+	}
+	if forwarded := req.Headers.Values("X-Forwarded-For"); len(forwarded) != 2 || forwarded[0] != "10.0.0.1" || forwarded[1] != "10.0.0.2" {
+		t.Errorf("X-Forwarded-For = %q", forwarded)
+	}
+	if string(req.Body) != "hello" {
+		t.Errorf("Body = %q", req.Body)
+	}
+}
+
+func TestReadRequestExtensionMethod(t *testing.T) {
+	req, err := readTestRequest(t, "PROPFIND /dav HTTP/1.1\r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "PROPFIND" {
+		t.Errorf("Method = %q, want PROPFIND", req.Method)
+	}
+}
+
+func TestReadRequestChunked(t *testing.T) {
+	req, err := readTestRequest(t, "POST /upload HTTP/1.1\r\nTransfer-Encoding: chunked\r\n\r\n"+
+		"5;name=value\r\nhello\r\n"+
+		"7\r\n, world\r\n"+
+		"0\r\nChecksum: abc123\r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(req.Body) != "hello, world" {
+		t.Errorf("Body = %q", req.Body)
+	}
+	if req.Trailers.Get("Checksum") != "abc123" {
+		t.Errorf("Trailers = %v", req.Trailers)
+	}
+}
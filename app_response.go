@@ -3,10 +3,12 @@ package pilot
 import (
 	"bufio"
 	"encoding/json"
+	"io"
 	"log"
 	"net"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // genericResponse represents the standard JSON response format used by framework response helpers.
@@ -30,16 +32,26 @@ type genericResponse struct {
 //
 // Fields:
 //   - StatusCode: HTTP status code using type-safe enum
-//   - Headers: Map of HTTP response headers
+//   - Headers: HTTP response headers; a field can be sent several times (see Header)
 //   - Body: Response content as byte array (used when Writer is nil)
 //   - Writer: Buffered reader for streaming responses (optional)
 //   - WriterSize: Size of streamed content when using Writer
+//
+// Responses whose length is not known in advance are created with StreamResponse or
+// StreamWriterResponse and sent with "Transfer-Encoding: chunked".
+//
+// When the response answers a HEAD request, Write sends the status line and headers,
+// including the Content-Length the body would have had, but not the body itself.
 type HttpResponse struct {
 	StatusCode StatusCode
-	Headers    map[string]string
+	Headers    Header
 	Body       []byte
 	Writer     *bufio.Reader
 	WriterSize int64
+	omitBody   bool
+	stream     func(io.Writer) error
+	untilClose bool
+	release    func()
 }
 
 // StringResponse creates a plain text HTTP response.
@@ -47,7 +59,7 @@ type HttpResponse struct {
 func StringResponse(body string) *HttpResponse {
 	res := NewHttpResponse()
 	res.StatusCode = StatusOK
-	res.Headers["Content-Type"] = "text/plain"
+	res.Headers.Set("Content-Type", "text/plain")
 	res.Body = []byte(body)
 	return res
 }
@@ -63,7 +75,7 @@ func ErrorResponse(body error) *HttpResponse {
 	json, _ := json.Marshal(errorResponse)
 	res := NewHttpResponse()
 	res.StatusCode = StatusInternalServerError
-	res.Headers["Content-Type"] = "application/json"
+	res.Headers.Set("Content-Type", "application/json")
 	res.Body = []byte(json)
 	return res
 }
@@ -90,7 +102,7 @@ func ErrorMessageResponse(body string) *HttpResponse {
 	json, _ := json.Marshal(errorResponse)
 	res := NewHttpResponse()
 	res.StatusCode = StatusInternalServerError
-	res.Headers["Content-Type"] = "application/json"
+	res.Headers.Set("Content-Type", "application/json")
 	res.Body = []byte(json)
 	return res
 }
@@ -117,6 +129,127 @@ func NotFoundResponse(err string) *HttpResponse {
 	return res
 }
 
+// CreatedResponse creates a 201 Created JSON response for a newly created resource.
+// The Location header is set to the resource's URL unless location is empty.
+//
+// Example:
+//
+//	location, _ := app.URL("user.show", "id", user.ID)
+//	return pilot.CreatedResponse(location, user)
+func CreatedResponse(location string, body any) *HttpResponse {
+	res := JsonResponse(body)
+	res.StatusCode = StatusCreated
+	if location != "" {
+		res.Headers.Set("Location", location)
+	}
+	return res
+}
+
+// AcceptedResponse creates a 202 Accepted JSON response.
+// Used when the request was accepted for processing that has not completed yet.
+func AcceptedResponse(body any) *HttpResponse {
+	res := JsonResponse(body)
+	res.StatusCode = StatusAccepted
+	return res
+}
+
+// NoContentResponse creates a 204 No Content response.
+// Used when the request succeeded and there is nothing to send back, such as after a delete.
+func NoContentResponse() *HttpResponse {
+	res := NewHttpResponse()
+	res.StatusCode = StatusNoContent
+	return res
+}
+
+// RedirectResponse creates a redirect to location with the given 3xx status, such as
+// StatusFound, StatusSeeOther, StatusTemporaryRedirect or StatusPermanentRedirect.
+//
+// Example:
+//
+//	return pilot.RedirectResponse("/login?next="+url.QueryEscape(req.Request.Path), pilot.StatusSeeOther)
+func RedirectResponse(location string, status StatusCode) *HttpResponse {
+	res := NewHttpResponse()
+	res.StatusCode = status
+	res.Headers.Set("Location", location)
+	return res
+}
+
+// NotModifiedResponse creates a 304 Not Modified response.
+// Used to answer a conditional request when the client's cached copy is still current.
+func NotModifiedResponse() *HttpResponse {
+	res := NewHttpResponse()
+	res.StatusCode = StatusNotModified
+	return res
+}
+
+// UnauthorizedResponse creates a 401 Unauthorized response.
+// Used when the request lacks valid credentials. RFC 9110 requires a WWW-Authenticate
+// header naming the accepted authentication scheme, which the caller should set.
+func UnauthorizedResponse(message string) *HttpResponse {
+	res := JsonResponse(genericResponse{
+		Status:  false,
+		Message: message,
+	})
+	res.StatusCode = StatusUnauthorized
+	return res
+}
+
+// ConflictResponse creates a 409 Conflict response.
+// Used when the request conflicts with the current state of the resource, such as a
+// duplicate unique value.
+func ConflictResponse(message string) *HttpResponse {
+	res := JsonResponse(genericResponse{
+		Status:  false,
+		Message: message,
+	})
+	res.StatusCode = StatusConflict
+	return res
+}
+
+// UnprocessableContentResponse creates a 422 Unprocessable Content response.
+// Used when the request body is well-formed but its contents cannot be processed.
+func UnprocessableContentResponse(message string) *HttpResponse {
+	res := JsonResponse(genericResponse{
+		Status:  false,
+		Message: message,
+	})
+	res.StatusCode = StatusUnprocessableContent
+	return res
+}
+
+// TooManyRequestsResponse creates a 429 Too Many Requests response.
+// The Retry-After header is set when retryAfter is positive.
+func TooManyRequestsResponse(message string, retryAfter time.Duration) *HttpResponse {
+	res := JsonResponse(genericResponse{
+		Status:  false,
+		Message: message,
+	})
+	res.StatusCode = StatusTooManyRequests
+	res.setRetryAfter(retryAfter)
+	return res
+}
+
+// ServiceUnavailableResponse creates a 503 Service Unavailable response.
+// Used during maintenance or overload; the Retry-After header is set when retryAfter
+// is positive.
+func ServiceUnavailableResponse(message string, retryAfter time.Duration) *HttpResponse {
+	res := JsonResponse(genericResponse{
+		Status:  false,
+		Message: message,
+	})
+	res.StatusCode = StatusServiceUnavailable
+	res.setRetryAfter(retryAfter)
+	return res
+}
+
+// setRetryAfter sets the Retry-After header to a delay in whole seconds, rounded up.
+func (self *HttpResponse) setRetryAfter(retryAfter time.Duration) {
+	if retryAfter > 0 {
+		seconds := (retryAfter + time.Second - 1) / time.Second
+		self.Headers.Set("Retry-After", strconv.FormatInt(int64(seconds), 10))
+	}
+}
+
 // ValidationErrorResponse creates a 400 Bad Request response from a validation error.
 // Sends the error message directly to the client for validation feedback.
 func ValidationErrorResponse(body error) *HttpResponse {
@@ -127,7 +260,7 @@ func ValidationErrorResponse(body error) *HttpResponse {
 	json, _ := json.Marshal(errorResponse)
 	res := NewHttpResponse()
 	res.StatusCode = StatusBadRequest
-	res.Headers["Content-Type"] = "application/json"
+	res.Headers.Set("Content-Type", "application/json")
 	res.Body = []byte(json)
 	return res
 }
@@ -146,7 +279,7 @@ func SuccessStringResponse(message string) *HttpResponse {
 func JsonResponse(body any) *HttpResponse {
 	res := NewHttpResponse()
 	res.StatusCode = StatusOK
-	res.Headers["Content-Type"] = "application/json"
+	res.Headers.Set("Content-Type", "application/json")
 	res.Body, _ = json.Marshal(body)
 	return res
 }
@@ -161,9 +294,83 @@ func BufferedResponse(writer *bufio.Reader, length int64) *HttpResponse {
 	return res
 }
 
-// SetHeader adds or updates an HTTP response header.
+// StreamResponse creates a response that copies the reader to the client as it is read,
+// for content whose length is not known in advance, such as a proxied upstream body.
+// The body is sent with "Transfer-Encoding: chunked", one chunk per read, so data reaches
+// the client as soon as the reader produces it. If the reader is an io.Closer, it is
+// closed once the body has been sent, and also when the body is never sent, such as for
+// a HEAD request.
+//
+// Parameters:
+//   - reader: Source of the response body, read until io.EOF
+//
+// Returns:
+//   - *HttpResponse: A 200 OK streaming response
+//
+// Example:
+//
+//	upstream, err := http.Get(backendURL)
+//	if err != nil {
+//	    return pilot.ErrorResponse(err)
+//	}
+//	response := pilot.StreamResponse(upstream.Body)
+//	response.SetHeader("Content-Type", upstream.Header.Get("Content-Type"))
+//	return response
+func StreamResponse(reader io.Reader) *HttpResponse {
+	res := StreamWriterResponse(func(w io.Writer) error {
+		_, err := io.Copy(w, reader)
+		return err
+	})
+	if closer, ok := reader.(io.Closer); ok {
+		res.release = func() { closer.Close() }
+	}
+	return res
+}
+
+// StreamWriterResponse creates a response whose body is produced by a callback writing to
+// the client, for generated content such as exports or server-sent progress. The callback
+// runs after the status line and headers have been sent, and every Write is sent to the
+// client immediately as one chunk of a "Transfer-Encoding: chunked" body.
+//
+// Because the headers are already sent, an error returned by the callback cannot change the
+// status code; the connection is closed without finishing the body instead, so the client
+// can tell the response is incomplete.
+//
+// Parameters:
+//   - write: Callback producing the body; it should stop when the request context is cancelled
+//
+// Returns:
+//   - *HttpResponse: A 200 OK streaming response
+//
+// Example:
+//
+//	return pilot.StreamWriterResponse(func(w io.Writer) error {
+//	    rows, err := req.Database.QueryContext(req.Context, "SELECT id, email FROM users")
+//	    if err != nil {
+//	        return err
+//	    }
+//	    defer rows.Close()
+//	    for rows.Next() {
+//	        var id int64
+//	        var email string
+//	        if err := rows.Scan(&id, &email); err != nil {
+//	            return err
+//	        }
+//	        fmt.Fprintf(w, "%d,%s\n", id, email)
+//	    }
+//	    return rows.Err()
+//	})
+func StreamWriterResponse(write func(w io.Writer) error) *HttpResponse {
+	res := NewHttpResponse()
+	res.StatusCode = StatusOK
+	res.stream = write
+	return res
+}
+
+// SetHeader adds or updates an HTTP response header, replacing any values it already has.
+// It is equivalent to Headers.Set; use Headers.Add to send a field more than once.
 func (self *HttpResponse) SetHeader(key string, value string) {
-	self.Headers[key] = value
+	self.Headers.Set(key, value)
 }
 
 // SetStatus updates the HTTP status code for this response.
@@ -183,46 +390,84 @@ func (self *HttpResponse) ApplyCors(origin *string, headers *string, methods *st
 // Formats and transmits the complete HTTP response including status line, headers, and body.
 // Used internally by the framework.
 func (self *HttpResponse) Write(stream net.Conn) {
+	self.write(stream)
+}
+
+// write sends the response and reports whether it was sent completely. After an error,
+// the connection must be closed, since the client cannot tell where the response ends.
+// Streaming responses are sent chunked, or delimited by closing the connection when
+// untilClose is set for clients that do not support chunked encoding. Responses whose
+// status does not allow content (1xx, 204 and 304) are sent without a body or framing.
+func (self *HttpResponse) write(stream io.Writer) error {
+	defer self.releaseBody()
 	var output strings.Builder
 	output.WriteString("HTTP/1.1 ")
 	output.WriteString(strconv.Itoa(int(self.StatusCode)))
 	output.WriteString(" ")
-	output.WriteString(StatusCodeDescriptions[self.StatusCode])
+	output.WriteString(self.StatusCode.Reason())
 	output.WriteString("\r\n")
-	for key, value := range self.Headers {
-		output.WriteString(key)
-		output.WriteString(": ")
-		output.WriteString(value)
-		output.WriteString("\r\n")
+	for key, values := range self.Headers {
+		if isFramingHeader(key) {
+			continue
+		}
+		for _, value := range values {
+			output.WriteString(key)
+			output.WriteString(": ")
+			output.WriteString(value)
+			output.WriteString("\r\n")
+		}
 	}
-	output.WriteString("Content-Length: ")
-	if self.Writer != nil {
-		output.WriteString(strconv.Itoa(int(self.WriterSize)))
+	bodyless := !self.StatusCode.allowsBody()
+	if bodyless {
+		// The response ends after the headers, so it needs no framing.
+	} else if self.stream != nil {
+		if !self.untilClose {
+			output.WriteString("Transfer-Encoding: chunked\r\n")
+		}
 	} else {
-		output.WriteString(strconv.Itoa(len(self.Body)))
-	}
-	output.WriteString("\r\n\r\n")
-
-	value := output.String()
-	write := 0
-	for write < len(value) {
-		n, err := stream.Write([]byte(value[write:]))
-		if err != nil {
-			return
+		output.WriteString("Content-Length: ")
+		if self.Writer != nil {
+			output.WriteString(strconv.Itoa(int(self.WriterSize)))
+		} else {
+			output.WriteString(strconv.Itoa(len(self.Body)))
 		}
-		write += n
+		output.WriteString("\r\n")
 	}
-	if self.Writer != nil {
-		self.Writer.WriteTo(stream)
-	} else {
-		write = 0
-		for write < len(self.Body) {
-			n, err := stream.Write(self.Body[write:])
-			if err != nil {
-				return
-			}
-			write += n
+	output.WriteString("\r\n")
+
+	if _, err := io.WriteString(stream, output.String()); err != nil {
+		return err
+	}
+	if self.omitBody || bodyless {
+		return nil
+	}
+	switch {
+	case self.stream != nil && self.untilClose:
+		return self.stream(stream)
+	case self.stream != nil:
+		chunked := &chunkedWriter{w: stream}
+		if err := self.stream(chunked); err != nil {
+			return err
 		}
+		return chunked.Close()
+	case self.Writer != nil:
+		_, err := self.Writer.WriteTo(stream)
+		return err
+	case len(self.Body) > 0:
+		_, err := stream.Write(self.Body)
+		return err
+	}
+	return nil
+}
+
+// releaseBody frees the resources behind a streamed body, such as the reader given to
+// StreamResponse. It runs once the response has been written, whether or not the body
+// was sent, and again does nothing.
+func (self *HttpResponse) releaseBody() {
+	if self.release != nil {
+		release := self.release
+		self.release = nil
+		release()
 	}
 }
 
@@ -231,7 +476,7 @@ func (self *HttpResponse) Write(stream net.Conn) {
 func NewHttpResponse() *HttpResponse {
 	return &HttpResponse{
 		StatusCode: StatusOK,
-		Headers:    make(map[string]string),
+		Headers:    Header{},
 		Body:       []byte{},
 	}
 }
@@ -0,0 +1,225 @@
+package pilot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func streamTestApplication() *Application[struct{}] {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/export", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StreamWriterResponse(func(w io.Writer) error {
+			for i := range 3 {
+				fmt.Fprintf(w, "row %d\n", i)
+			}
+			return nil
+		})
+	})
+	app.Routes.AddRoute(Get, "/proxy", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StreamResponse(io.NopCloser(strings.NewReader("proxied")))
+	})
+	app.Routes.AddRoute(Get, "/broken", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StreamWriterResponse(func(w io.Writer) error {
+			io.WriteString(w, "partial")
+			return errors.New("export failed")
+		})
+	})
+	return app
+}
+
+func TestStreamResponse(t *testing.T) {
+	client, reader := startTestConnection(t, streamTestApplication())
+	for path, want := range map[string]string{"/export": "row 0\nrow 1\nrow 2\n", "/proxy": "proxied"} {
+		io.WriteString(client, "GET "+path+" HTTP/1.1\r\n\r\n")
+		response, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(response.Body)
+		if err != nil || string(body) != want {
+			t.Errorf("GET %s = %q %v, want %q", path, body, err, want)
+		}
+		if len(response.TransferEncoding) != 1 || response.TransferEncoding[0] != "chunked" || response.ContentLength != -1 {
+			t.Errorf("GET %s framing = %v, length %d", path, response.TransferEncoding, response.ContentLength)
+		}
+		if response.Close {
+			t.Errorf("GET %s closed the connection", path)
+		}
+	}
+
+	io.WriteString(client, "GET /broken HTTP/1.1\r\n\r\n")
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(response.Body); err == nil {
+		t.Error("failed stream was terminated like a complete body")
+	}
+}
+
+func TestStreamResponseHTTP10(t *testing.T) {
+	client, reader := startTestConnection(t, streamTestApplication())
+	io.WriteString(client, "GET /export HTTP/1.0\r\nConnection: keep-alive\r\n\r\n")
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	if string(body) != "row 0\nrow 1\nrow 2\n" || len(response.TransferEncoding) != 0 || !response.Close {
+		t.Errorf("HTTP/1.0 stream = %q, framing %v, close %v", body, response.TransferEncoding, response.Close)
+	}
+}
+
+func TestStreamResponseServeHTTP(t *testing.T) {
+	server := httptest.NewServer(streamTestApplication())
+	defer server.Close()
+	response, err := http.Get(server.URL + "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if string(body) != "row 0\nrow 1\nrow 2\n" || response.ContentLength != -1 {
+		t.Errorf("GET /export = %q, length %d", body, response.ContentLength)
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	response := StringResponse("hello")
+	response.Headers.Add("Set-Cookie", "session=abc; HttpOnly")
+	response.Headers.Add("set-cookie", "theme=dark")
+	response.SetHeader("Content-Length", "999")
+
+	var output strings.Builder
+	if err := response.write(&output); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := http.ReadResponse(bufio.NewReader(strings.NewReader(output.String())), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookies := parsed.Header.Values("Set-Cookie"); len(cookies) != 2 || cookies[0] != "session=abc; HttpOnly" || cookies[1] != "theme=dark" {
+		t.Errorf("Set-Cookie = %q", cookies)
+	}
+	if parsed.ContentLength != 5 {
+		t.Errorf("Content-Length = %d, want 5", parsed.ContentLength)
+	}
+
+	recorder := httptest.NewRecorder()
+	response.writeStd(recorder)
+	if cookies := recorder.Result().Header.Values("Set-Cookie"); len(cookies) != 2 {
+		t.Errorf("ServeHTTP Set-Cookie = %q", cookies)
+	}
+}
+
+func TestResponseStatusLine(t *testing.T) {
+	unregistered := StringResponse("ok")
+	unregistered.SetStatus(299)
+	tests := []struct {
+		name     string
+		response *HttpResponse
+		status   string
+		length   int64
+	}{
+		{"created", CreatedResponse("/users/1", map[string]int{"id": 1}), "201 Created", 8},
+		{"unregistered", unregistered, "299 Success", 2},
+		{"no content", NoContentResponse(), "204 No Content", 0},
+		{"not modified", NotModifiedResponse(), "304 Not Modified", 0},
+		{"redirect", RedirectResponse("/login", StatusSeeOther), "303 See Other", 0},
+		{"too many requests", TooManyRequestsResponse("slow down", 1500*time.Millisecond), "429 Too Many Requests", 38},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			if err := tt.response.write(&output); err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := http.ReadResponse(bufio.NewReader(strings.NewReader(output.String())), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Status != tt.status || parsed.ContentLength != tt.length {
+				t.Errorf("status line %q, length %d; want %q, %d", parsed.Status, parsed.ContentLength, tt.status, tt.length)
+			}
+		})
+	}
+
+	var output strings.Builder
+	NoContentResponse().write(&output)
+	if strings.Contains(output.String(), "Content-Length") {
+		t.Errorf("204 response has framing: %q", output.String())
+	}
+	if got := CreatedResponse("/users/1", nil).Headers.Get("Location"); got != "/users/1" {
+		t.Errorf("Created Location = %q", got)
+	}
+	if got := TooManyRequestsResponse("", 1500*time.Millisecond).Headers.Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func (self *closeRecorder) Close() error {
+	close(self.closed)
+	return nil
+}
+
+func TestStreamResponseClosedWithoutBody(t *testing.T) {
+	newReader := func() *closeRecorder {
+		return &closeRecorder{Reader: strings.NewReader("body"), closed: make(chan struct{})}
+	}
+	assertReaderClosed := func(t *testing.T, reader *closeRecorder) {
+		t.Helper()
+		select {
+		case <-reader.closed:
+		case <-time.After(time.Second):
+			t.Error("stream reader was not closed")
+		}
+	}
+
+	t.Run("head", func(t *testing.T) {
+		reader := newReader()
+		app := newTestApplication()
+		app.Routes.AddRoute(Get, "/download", func(req *RouteRequest[struct{}]) *HttpResponse {
+			return StreamResponse(reader)
+		})
+		client, responses := startTestConnection(t, app)
+		io.WriteString(client, "HEAD /download HTTP/1.1\r\n\r\n")
+		if _, err := http.ReadResponse(responses, &http.Request{Method: "HEAD"}); err != nil {
+			t.Fatal(err)
+		}
+		assertReaderClosed(t, reader)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		reader := newReader()
+		response := StreamResponse(reader)
+		response.SetStatus(StatusNoContent)
+		response.writeStd(httptest.NewRecorder())
+		assertReaderClosed(t, reader)
+	})
+
+	t.Run("handler timeout", func(t *testing.T) {
+		reader := newReader()
+		app := newTestApplication()
+		app.HandlerTimeout = 10 * time.Millisecond
+		app.Routes.AddRoute(Get, "/slow", func(req *RouteRequest[struct{}]) *HttpResponse {
+			<-req.Context.Done()
+			return StreamResponse(reader)
+		})
+		if response := serveTestRequest(app, Get, "/slow"); response.StatusCode != StatusServiceUnavailable {
+			t.Fatalf("GET /slow = %d", response.StatusCode)
+		}
+		assertReaderClosed(t, reader)
+	})
+}
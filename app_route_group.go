@@ -1,5 +1,10 @@
 package pilot
 
+import (
+	"slices"
+	"strings"
+)
+
 // RouteGroup represents a collection of related routes that can be mounted together
 // under a common path prefix. This enables modular route organization and helps
 // structure large applications with multiple feature areas or API versions.
@@ -13,6 +18,8 @@ package pilot
 //
 // Fields:
 //   - Routes: Slice of grouped routes with their methods, paths, handlers, and middleware
+//   - Middleware: Middleware applied to every route in the group and its subgroups
+//   - Groups: Nested groups mounted below this group's prefix
 //
 // Example:
 //
@@ -23,7 +30,198 @@ package pilot
 //	)
 //	app.AddRouteGroup("/user", userRoutes)
 type RouteGroup[RouteState any] struct {
-	Routes []GroupedRoute[RouteState]
+	Routes     []GroupedRoute[RouteState]
+	Middleware []MiddlewareFn[RouteState]
+	Groups     []RouteSubgroup[RouteState]
+}
+
+// RouteSubgroup is a RouteGroup nested inside another group at a path prefix
+// relative to the parent group's prefix.
+//
+// Fields:
+//   - Prefix: Path prefix relative to the parent group (e.g., "/v1", "/admin")
+//   - Group: The nested group, which may contain further subgroups
+type RouteSubgroup[RouteState any] struct {
+	Prefix string
+	Group  *RouteGroup[RouteState]
+}
+
+// Use appends group-scoped middleware that runs for every route in this group and
+// all of its subgroups. Group middleware runs before the middleware attached to the
+// individual GroupedRoute, and outer groups run before inner ones.
+//
+// Parameters:
+//   - middleware: Middleware functions to apply to the whole group, in order
+//
+// Returns:
+//   - *RouteGroup[RouteState]: The same group, for chaining
+//
+// Example:
+//
+//	admin := pilot.NewRouteGroup(
+//	    pilot.GetRoute("/users", listUsers),
+//	    pilot.DeleteRoute("/users/:id", deleteUser),
+//	).Use(authMiddleware, adminMiddleware)
+func (self *RouteGroup[RouteState]) Use(middleware ...MiddlewareFn[RouteState]) *RouteGroup[RouteState] {
+	self.Middleware = append(self.Middleware, middleware...)
+	return self
+}
+
+// Group nests another route group below this group at the given prefix.
+// The nested group's routes are mounted at parentPrefix + prefix + route, and run
+// this group's middleware before their own group and route middleware.
+//
+// Parameters:
+//   - prefix: Path prefix relative to this group (e.g., "/v1")
+//   - group: The group to nest
+//
+// Returns:
+//   - *RouteGroup[RouteState]: The parent group, for chaining
+//
+// Example:
+//
+//	api := pilot.NewRouteGroup(pilot.GetRoute("/health", healthCheck)).
+//	    Use(loggingMiddleware).
+//	    Group("/v1", pilot.NewRouteGroup(
+//	        pilot.GetRoute("/users", listUsers),
+//	    ).Group("/admin", pilot.NewRouteGroup(
+//	        pilot.DeleteRoute("/users/:id", deleteUser),
+//	    ).Use(authMiddleware)))
+//	app.AddRouteGroup("/api", api)
+//	// Creates: /api/health, /api/v1/users, /api/v1/admin/users/:id (with auth)
+func (self *RouteGroup[RouteState]) Group(prefix string, group *RouteGroup[RouteState]) *RouteGroup[RouteState] {
+	self.Groups = append(self.Groups, RouteSubgroup[RouteState]{
+		Prefix: prefix,
+		Group:  group,
+	})
+	return self
+}
+
+// Flatten resolves this group and all nested subgroups into a flat list of routes.
+// Each returned route has its full path relative to the given prefix and a middleware
+// chain composed of every enclosing group's middleware followed by its own.
+//
+// Parameters:
+//   - prefix: Path prefix the group is mounted at (e.g., "/api")
+//
+// Returns:
+//   - []GroupedRoute[RouteState]: Routes with full paths and composed middleware,
+//     listed depth-first in declaration order
+func (self *RouteGroup[RouteState]) Flatten(prefix string) []GroupedRoute[RouteState] {
+	return self.flatten(prefix, []MiddlewareFn[RouteState]{})
+}
+
+// flatten accumulates the routes of this group and its subgroups, prepending the
+// middleware inherited from enclosing groups to each route's own middleware.
+func (self *RouteGroup[RouteState]) flatten(prefix string, inherited []MiddlewareFn[RouteState]) []GroupedRoute[RouteState] {
+	middleware := append(slices.Clip(inherited), self.Middleware...)
+	routes := make([]GroupedRoute[RouteState], 0, len(self.Routes))
+	for i := range self.Routes {
+		route := self.Routes[i]
+		route.Route = joinRoutePath(prefix, route.Route)
+		route.Middleware = append(slices.Clip(middleware), route.Middleware...)
+		routes = append(routes, route)
+	}
+	for i := range self.Groups {
+		routes = append(routes, self.Groups[i].Group.flatten(joinRoutePath(prefix, self.Groups[i].Prefix), middleware)...)
+	}
+	return routes
+}
+
+// joinRoutePath joins a group prefix and a route path with exactly one "/" between them,
+// making sure the result starts with "/". A route of "" or "/" is mounted at the prefix
+// itself, keeping the prefix's own trailing slash form, so GetRoute("/") mounted at
+// "/users" serves "/users" rather than "/users/".
+func joinRoutePath(prefix string, route string) string {
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if route == "" || route == "/" {
+		return prefix
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + strings.TrimPrefix(route, "/")
+}
+
+// AddRouteGroup registers all routes from a RouteGroup and its nested subgroups under a
+// common prefix. The group tree is flattened with RouteGroup.Flatten, so every route is
+// registered with its full path and with group middleware composed in order: outermost
+// group first, then each nested group, then the route's own middleware.
+//
+// Routes are registered in the order returned by Flatten, atomically: if AddRouteHandler
+// rejects any route, that error is returned and none of the group's routes are registered,
+// and requests served concurrently never see a partially mounted group.
+//
+// Parameters:
+//   - prefix: URL path prefix for all routes in the group (e.g., "/api")
+//   - rg: RouteGroup to mount
+//
+// Returns:
+//   - error: The first registration error, or nil if every route was registered
+//
+// Example:
+//
+//	v1 := pilot.NewRouteGroup(pilot.GetRoute("/users", listUsers)).
+//	    Group("/admin", pilot.NewRouteGroup(pilot.GetRoute("/stats", getStats)).Use(authMiddleware))
+//	routes.AddRouteGroup("/api/v1", v1)
+//	// GET /api/v1/users       → listUsers
+//	// GET /api/v1/admin/stats → authMiddleware → getStats
+func (self *RouteCollection[RouteState]) AddRouteGroup(prefix string, rg *RouteGroup[RouteState]) error {
+	routes := rg.Flatten(prefix)
+	return self.Update(func(staging *RouteCollection[RouteState]) error {
+		for i := range routes {
+			err := staging.AddRouteHandler(routes[i].Method, routes[i].Route, RouteHandler[RouteState]{
+				Handler:    routes[i].Handler,
+				Middleware: routes[i].Middleware,
+				Name:       routes[i].Name,
+				Metadata:   routes[i].Metadata,
+				Version:    routes[i].Version,
+				Limits:     routes[i].Limits,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveRouteGroup unregisters every route of a RouteGroup that was mounted with
+// AddRouteGroup at the same prefix. The group is flattened the same way, and all of its
+// routes are removed atomically: if any of them is not registered, a wrapped
+// ErrRouteNotFound is returned and the collection is left unchanged. Like RemoveRoute,
+// this removes every version of a method and path at once.
+//
+// Parameters:
+//   - prefix: URL path prefix the group was mounted at
+//   - rg: The mounted RouteGroup
+//
+// Returns:
+//   - error: The first route that could not be removed, or nil if every route was removed
+//
+// Example:
+//
+//	routes.AddRouteGroup("/plugins/billing", billing.Routes())
+//	// ...later, when the plugin is unloaded:
+//	routes.RemoveRouteGroup("/plugins/billing", billing.Routes())
+func (self *RouteCollection[RouteState]) RemoveRouteGroup(prefix string, rg *RouteGroup[RouteState]) error {
+	routes := rg.Flatten(prefix)
+	return self.Update(func(staging *RouteCollection[RouteState]) error {
+		removed := map[string]bool{}
+		for i := range routes {
+			key := string(routes[i].Method) + " " + routes[i].Route
+			if removed[key] {
+				continue
+			}
+			removed[key] = true
+			if err := staging.RemoveRoute(routes[i].Method, routes[i].Route); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // NewRouteGroup creates a new route group from a variable number of grouped routes.
@@ -93,7 +291,7 @@ func GetRoute[RouteState RouteStateCompatible](path string, handler RouteHandler
 //
 //	createUserRoute := pilot.PostRoute("/", func(req *pilot.RouteRequest[AppState]) *pilot.HttpResponse {
 //	    // Parse and create new user
-//	    return pilot.CreatedResponse(newUser)
+//	    return pilot.CreatedResponse("/users/"+newUser.ID, newUser)
 //	}, authMiddleware, validationMiddleware)
 func PostRoute[RouteState RouteStateCompatible](path string, handler RouteHandlerFn[RouteState], middleware ...MiddlewareFn[RouteState]) GroupedRoute[RouteState] {
 	return GroupedRoute[RouteState]{
@@ -202,6 +400,10 @@ func DeleteRoute[RouteState RouteStateCompatible](path string, handler RouteHand
 //   - Method: HTTP method this route handles (GET, POST, PUT, PATCH, DELETE)
 //   - Handler: Main function that processes requests to this route
 //   - Middleware: Slice of middleware functions applied before the handler
+//   - Name: Optional route name for URL generation, usually set with Named
+//   - Metadata: Optional route metadata, usually set with WithMetadata
+//   - Version: Optional API version this route serves, usually set with WithVersion
+//   - Limits: Optional request size limits for this route, usually set with WithLimits
 //
 // When a RouteGroup is mounted with AddRouteGroup, each GroupedRoute is converted
 // to a full route registration with the appropriate prefix path and middleware chain.
@@ -218,4 +420,93 @@ type GroupedRoute[RouteState any] struct {
 	Method     HttpMethod
 	Handler    RouteHandlerFn[RouteState]
 	Middleware []MiddlewareFn[RouteState]
+	Name       string
+	Metadata   RouteMetadata
+	Version    string
+	Limits     RequestLimits
+}
+
+// Named returns a copy of the grouped route with the given route name, so the full
+// path it is mounted at can later be generated with Application.URL. Because the
+// name is resolved after mounting, generated URLs always include the group prefix.
+//
+// Parameters:
+//   - name: Unique route name (e.g., "user.show")
+//
+// Returns:
+//   - GroupedRoute[RouteState]: The same route configuration with Name set
+//
+// Example:
+//
+//	users := pilot.NewRouteGroup(
+//	    pilot.GetRoute("/:id", getUser).Named("user.show"),
+//	)
+//	app.AddRouteGroup("/users", users)
+//	location, _ := app.URL("user.show", "id", 42) // "/users/42"
+func (self GroupedRoute[RouteState]) Named(name string) GroupedRoute[RouteState] {
+	self.Name = name
+	return self
+}
+
+// WithMetadata returns a copy of the grouped route with the given metadata, which is
+// registered with the route when the group is mounted. See RouteMetadata.
+//
+// Parameters:
+//   - metadata: Description of the route, such as its summary, tags and scopes
+//
+// Returns:
+//   - GroupedRoute[RouteState]: The same route configuration with Metadata set
+//
+// Example:
+//
+//	users := pilot.NewRouteGroup(
+//	    pilot.DeleteRoute("/:id", deleteUser).WithMetadata(pilot.RouteMetadata{
+//	        Summary: "Delete a user",
+//	        Tags:    []string{"users"},
+//	        Scopes:  []string{"users:write"},
+//	    }),
+//	).Use(requireScopes)
+func (self GroupedRoute[RouteState]) WithMetadata(metadata RouteMetadata) GroupedRoute[RouteState] {
+	self.Metadata = metadata
+	return self
+}
+
+// WithVersion returns a copy of the grouped route that serves the given API version, so
+// several versions of the same method and path can be declared in a group. See
+// RouteHandler.Version.
+//
+// Parameters:
+//   - version: Version this route serves (e.g., "2")
+//
+// Returns:
+//   - GroupedRoute[RouteState]: The same route configuration with Version set
+//
+// Example:
+//
+//	users := pilot.NewRouteGroup(
+//	    pilot.GetRoute("/:id", getUserV1).WithVersion("1"),
+//	    pilot.GetRoute("/:id", getUserV2).WithVersion("2"),
+//	)
+func (self GroupedRoute[RouteState]) WithVersion(version string) GroupedRoute[RouteState] {
+	self.Version = version
+	return self
+}
+
+// WithLimits returns a copy of the grouped route with the given request size limits, which
+// replace the application's limits for this route where they are non-zero. See RequestLimits.
+//
+// Parameters:
+//   - limits: Limits for requests to this route
+//
+// Returns:
+//   - GroupedRoute[RouteState]: The same route configuration with Limits set
+//
+// Example:
+//
+//	media := pilot.NewRouteGroup(
+//	    pilot.PostRoute("/videos", uploadVideo).WithLimits(pilot.RequestLimits{MaxBodyBytes: 2 << 30}),
+//	)
+func (self GroupedRoute[RouteState]) WithLimits(limits RequestLimits) GroupedRoute[RouteState] {
+	self.Limits = limits
+	return self
 }
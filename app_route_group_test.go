@@ -0,0 +1,62 @@
+package pilot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNestedRouteGroups(t *testing.T) {
+	calls := []string{}
+	record := func(name string) MiddlewareFn[struct{}] {
+		return func(req *RouteRequest[struct{}]) *HttpResponse {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	admin := NewRouteGroup(
+		DeleteRoute("/users/:id", noopHandler, record("route")),
+	).Use(record("admin"))
+	v1 := NewRouteGroup(
+		GetRoute("/users", noopHandler),
+	).Use(record("v1")).Group("/admin", admin)
+	api := NewRouteGroup(
+		GetRoute("/health", noopHandler),
+	).Use(record("api")).Group("v1/", v1)
+
+	app := newTestApplication()
+	app.AddRouteGroup("/api", api)
+
+	paths := []string{}
+	for _, route := range api.Flatten("/api") {
+		paths = append(paths, string(route.Method)+" "+route.Route)
+	}
+	wantPaths := []string{"GET /api/health", "GET /api/v1/users", "DELETE /api/v1/admin/users/:id"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Flatten = %v, want %v", paths, wantPaths)
+	}
+
+	serveTestRequest(app, Delete, "/api/v1/admin/users/42")
+	if want := []string{"api", "v1", "admin", "route"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("middleware order = %v, want %v", calls, want)
+	}
+
+	calls = calls[:0]
+	serveTestRequest(app, Get, "/api/health")
+	if want := []string{"api"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("middleware order = %v, want %v", calls, want)
+	}
+}
+
+func TestRouteGroupRootRoute(t *testing.T) {
+	app := newTestApplication()
+	app.TrailingSlash = TrailingSlashRedirect
+	app.AddRouteGroup("/users", NewRouteGroup(GetRoute("/", noopHandler)))
+	app.AddRouteGroup("/docs/", NewRouteGroup(GetRoute("/", noopHandler)))
+	if response := serveTestRequest(app, Get, "/users"); response.StatusCode != StatusOK {
+		t.Errorf("GET /users = %d %q", response.StatusCode, response.Headers.Get("Location"))
+	}
+	if response := serveTestRequest(app, Get, "/docs/"); response.StatusCode != StatusOK {
+		t.Errorf("GET /docs/ = %d %q", response.StatusCode, response.Headers.Get("Location"))
+	}
+}
@@ -0,0 +1,72 @@
+package pilot
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RouteMetadata describes a route beyond what is needed to dispatch it. It is declared once
+// with the registration, through RouteHandler.Metadata or GroupedRoute.WithMetadata, and
+// can then drive documentation generation (see RouteCollection.List), authorization and
+// rate limiting middleware (see RouteRequest.Metadata) and deprecation headers.
+//
+// When Deprecated or Sunset is set, every response from the route carries the matching
+// Deprecation (RFC 9745) or Sunset (RFC 8594) header, unless the handler set it itself.
+//
+// Fields:
+//   - Summary: Short human-readable description of the endpoint
+//   - Tags: Labels used to group endpoints, e.g. in generated documentation
+//   - Scopes: Permissions a caller needs, for authorization middleware to enforce
+//   - RateLimitClass: Name of the rate limit bucket the endpoint belongs to
+//   - Deprecated: When the endpoint was or will be deprecated; zero if it is not deprecated
+//   - Sunset: When the endpoint is expected to stop responding; zero if not scheduled
+//   - Values: Application-defined values, read with MetadataValue
+type RouteMetadata struct {
+	Summary        string
+	Tags           []string
+	Scopes         []string
+	RateLimitClass string
+	Deprecated     time.Time
+	Sunset         time.Time
+	Values         map[string]any
+}
+
+// MetadataValue returns an application-defined metadata value converted to type T.
+// The second result is false if the key is not set or holds a value of a different type.
+//
+// Parameters:
+//   - metadata: Metadata of the route, usually RouteRequest.Metadata
+//   - key: Key the value was stored under in RouteMetadata.Values
+//
+// Returns:
+//   - T: The stored value, or the zero value of T
+//   - bool: Whether a value of type T was stored under key
+//
+// Example:
+//
+//	routes.AddRouteHandler(pilot.Get, "/reports", pilot.RouteHandler[AppState]{
+//	    Handler:  getReports,
+//	    Metadata: pilot.RouteMetadata{Values: map[string]any{"cacheTTL": 5 * time.Minute}},
+//	})
+//
+//	ttl, ok := pilot.MetadataValue[time.Duration](req.Metadata, "cacheTTL")
+func MetadataValue[T any](metadata RouteMetadata, key string) (T, bool) {
+	value, ok := metadata.Values[key].(T)
+	return value, ok
+}
+
+// applyHeaders adds the Deprecation and Sunset headers declared by the metadata to a
+// response, keeping any value the handler already set.
+func (self *RouteMetadata) applyHeaders(response *HttpResponse) {
+	if !self.Deprecated.IsZero() {
+		if !response.Headers.Has("Deprecation") {
+			response.SetHeader("Deprecation", "@"+strconv.FormatInt(self.Deprecated.Unix(), 10))
+		}
+	}
+	if !self.Sunset.IsZero() {
+		if !response.Headers.Has("Sunset") {
+			response.SetHeader("Sunset", self.Sunset.UTC().Format(http.TimeFormat))
+		}
+	}
+}
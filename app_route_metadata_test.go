@@ -0,0 +1,63 @@
+package pilot
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteMetadata(t *testing.T) {
+	app := newTestApplication()
+	requireScopes := func(req *RouteRequest[struct{}]) *HttpResponse {
+		for _, scope := range req.Metadata.Scopes {
+			if !slices.Contains(strings.Split(req.Request.Headers.Get("X-Scopes"), ","), scope) {
+				response := StringResponse("missing scope " + scope)
+				response.SetStatus(StatusForbidden)
+				return response
+			}
+		}
+		return nil
+	}
+	deprecated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	group := NewRouteGroup(
+		DeleteRoute("/:id", func(req *RouteRequest[struct{}]) *HttpResponse {
+			ttl, _ := MetadataValue[time.Duration](req.Metadata, "ttl")
+			return StringResponse(req.Metadata.Summary + " " + ttl.String())
+		}).WithMetadata(RouteMetadata{
+			Summary:    "Delete a user",
+			Tags:       []string{"users"},
+			Scopes:     []string{"users:write"},
+			Deprecated: deprecated,
+			Sunset:     sunset,
+			Values:     map[string]any{"ttl": time.Minute},
+		}),
+	).Use(requireScopes)
+	if err := app.AddRouteGroup("/users", group); err != nil {
+		t.Fatal(err)
+	}
+
+	request := &HttpRequest{Method: Delete, Path: "/users/1", Headers: Header{"X-Scopes": {"users:read,users:write"}}}
+	response := app.serveRequest(context.Background(), request, func(string) {})
+	if string(response.Body) != "Delete a user 1m0s" {
+		t.Errorf("DELETE /users/1 = %d %q", response.StatusCode, response.Body)
+	}
+	if response.Headers.Get("Deprecation") != "@1735689600" || response.Headers.Get("Sunset") != "Thu, 01 Jan 2026 00:00:00 GMT" {
+		t.Errorf("deprecation headers = %q %q", response.Headers.Get("Deprecation"), response.Headers.Get("Sunset"))
+	}
+
+	response = serveTestRequest(app, Delete, "/users/1")
+	if response.StatusCode != StatusForbidden || response.Headers.Get("Sunset") == "" {
+		t.Errorf("DELETE without scopes = %d %v", response.StatusCode, response.Headers)
+	}
+
+	routes := app.Routes.List()
+	if len(routes) != 1 || routes[0].Metadata.Summary != "Delete a user" || !slices.Equal(routes[0].Metadata.Tags, []string{"users"}) {
+		t.Errorf("List() = %+v", routes)
+	}
+	if _, ok := MetadataValue[string](routes[0].Metadata, "ttl"); ok {
+		t.Error("MetadataValue returned a value of the wrong type")
+	}
+}
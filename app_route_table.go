@@ -0,0 +1,83 @@
+package pilot
+
+import (
+	"cmp"
+	"slices"
+)
+
+// RouteInfo describes a single registered route, one entry per path and method.
+// It is the structured counterpart to PrintTree, intended for tooling and tests
+// that need to inspect or assert an application's route surface.
+//
+// Fields:
+//   - Method: HTTP method the handler is registered for
+//   - Path: Full route pattern, including parameter segments (e.g., "/users/:id")
+//   - Name: Route name used for URL generation, or empty if unnamed
+//   - Middleware: Number of middleware functions that run before the handler
+//   - Metadata: Metadata declared for the handler, e.g. for documentation generation
+//   - Version: API version of the handler, or empty for an unversioned handler
+type RouteInfo struct {
+	Method     HttpMethod
+	Path       string
+	Name       string
+	Middleware int
+	Metadata   RouteMetadata
+	Version    string
+}
+
+// List returns every registered route in the collection, sorted by path, method and version.
+// Each version of a versioned handler is listed as a separate entry.
+// Implicit responses such as automatic HEAD and OPTIONS handling are not included; only
+// handlers that were registered explicitly are listed.
+//
+// Returns:
+//   - []RouteInfo: One entry per registered path and method
+//
+// Example:
+//
+//	for _, route := range app.Routes.List() {
+//	    fmt.Printf("%-7s %s (%d middleware)\n", route.Method, route.Path, route.Middleware)
+//	}
+func (self *RouteCollection[RouteState]) List() []RouteInfo {
+	self.mu.Lock()
+	routes := []RouteInfo{}
+	for i := range self.Routes {
+		self.Routes[i].list("", &routes)
+	}
+	self.mu.Unlock()
+	slices.SortFunc(routes, func(a, b RouteInfo) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), cmp.Compare(a.Method, b.Method), cmp.Compare(a.Version, b.Version))
+	})
+	return routes
+}
+
+// list appends the handlers of this node and its descendants to routes, building each
+// full path from the path components of the enclosing nodes.
+func (self *Route[RouteState]) list(prefix string, routes *[]RouteInfo) {
+	path := prefix + "/" + self.PathComponent
+	for method, handler := range self.Handlers {
+		if handler.Version == "" {
+			*routes = append(*routes, handler.info(method, path))
+		}
+	}
+	for method, versions := range self.Versions {
+		for _, handler := range versions {
+			*routes = append(*routes, handler.info(method, path))
+		}
+	}
+	for i := range self.Children {
+		self.Children[i].list(path, routes)
+	}
+}
+
+// info describes the handler registered for method at path.
+func (self *RouteHandler[RouteState]) info(method HttpMethod, path string) RouteInfo {
+	return RouteInfo{
+		Method:     method,
+		Path:       path,
+		Name:       self.Name,
+		Middleware: len(self.Middleware),
+		Metadata:   self.Metadata,
+		Version:    self.Version,
+	}
+}
@@ -0,0 +1,194 @@
+package pilot
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ReplaceRoute registers a handler for the specified HTTP method and path, replacing the
+// handler that is already registered for them instead of reporting a conflict. Requests
+// that are already being handled finish with the previous handler; later requests use
+// the new one. This is intended for hot swapping handlers while the application is serving
+// traffic, such as switching an endpoint's implementation behind a feature flag.
+//
+// All other registration rules of AddRouteHandler still apply. If the previous handler
+// had a route name that the new one does not keep, the name is released.
+//
+// Parameters:
+//   - method: HTTP method the handler responds to
+//   - path: URL path pattern supporting parameters (e.g., "/users/:id")
+//   - handler: Handler, middleware and registration options for this route
+//
+// Returns:
+//   - error: A wrapped ErrRouteConflict or ErrInvalidRoute if the route cannot be
+//     registered, or nil on success
+//
+// Example:
+//
+//	flags.OnChange("search-v2", func(enabled bool) {
+//	    handler := searchV1
+//	    if enabled {
+//	        handler = searchV2
+//	    }
+//	    app.Routes.ReplaceRoute(pilot.Get, "/search", pilot.RouteHandler[AppState]{Handler: handler})
+//	})
+func (self *RouteCollection[RouteState]) ReplaceRoute(method HttpMethod, path string, handler RouteHandler[RouteState]) error {
+	return self.setRouteHandler(method, path, handler, true)
+}
+
+// RemoveRoute unregisters the handler for the specified HTTP method and path, including all
+// of its versions. The path must be the pattern the route was registered with
+// (e.g., "/users/:id"), not a request path.
+// Nodes left without handlers or children are pruned, and the route's name is released
+// once no remaining handler on the path uses it.
+//
+// Requests that already matched the route finish normally; later requests no longer see it
+// and receive 404 or 405 responses as if it had never been registered.
+//
+// Parameters:
+//   - method: HTTP method of the handler to remove
+//   - path: Route pattern the handler was registered with
+//
+// Returns:
+//   - error: A wrapped ErrRouteNotFound if no handler is registered for method and path
+//
+// Example:
+//
+//	plugin.OnUnload(func() {
+//	    app.Routes.RemoveRoute(pilot.Post, "/plugins/billing/webhook")
+//	})
+func (self *RouteCollection[RouteState]) RemoveRoute(method HttpMethod, path string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	routes, removed, found := removeRoute(self.Routes, PathListFromString(path), method)
+	if !found {
+		return fmt.Errorf("%w: %s %s", ErrRouteNotFound, method, path)
+	}
+	self.Routes = routes
+	self.index.Store(nil)
+	node := findLiteral(self.Routes, PathListFromString(path))
+	for _, handler := range removed {
+		if node != nil {
+			self.forgetName(node, handler.Name)
+		} else {
+			delete(self.names, handler.Name)
+		}
+	}
+	return nil
+}
+
+// Update applies several changes to the collection atomically. The function receives a
+// staging copy of the collection to register, replace and remove routes on; if it returns
+// nil, the staged route table replaces the current one in a single step, and if it returns
+// an error or panics, every staged change is discarded. Requests being served concurrently
+// see either the complete previous table or the complete new one, never a partial update.
+//
+// Other updates to the collection wait until fn returns, so fn must only modify routes
+// through the staging collection it is given; calling methods on the original collection
+// from inside fn deadlocks.
+//
+// Parameters:
+//   - fn: Function applying the changes to the staging collection
+//
+// Returns:
+//   - error: The error returned by fn, in which case the collection is unchanged
+//
+// Example:
+//
+//	err := app.Routes.Update(func(routes *pilot.RouteCollection[AppState]) error {
+//	    if err := routes.RemoveRoute(pilot.Get, "/reports"); err != nil {
+//	        return err
+//	    }
+//	    return routes.AddRouteGroup("/reports", reportsV2)
+//	})
+func (self *RouteCollection[RouteState]) Update(fn func(routes *RouteCollection[RouteState]) error) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	staging := &RouteCollection[RouteState]{
+		Routes: slices.Clone(self.Routes),
+		Strict: self.Strict,
+		names:  maps.Clone(self.names),
+	}
+	if err := fn(staging); err != nil {
+		return err
+	}
+	self.Routes = staging.Routes
+	self.names = staging.names
+	self.index.Store(nil)
+	return nil
+}
+
+// forgetName releases a route name unless another handler on the node still uses it.
+// The caller must hold the lock.
+func (self *RouteCollection[RouteState]) forgetName(node *Route[RouteState], name string) {
+	if name == "" {
+		return
+	}
+	for _, handler := range node.Handlers {
+		if handler.Name == name {
+			return
+		}
+	}
+	for _, versions := range node.Versions {
+		for _, handler := range versions {
+			if handler.Name == name {
+				return
+			}
+		}
+	}
+	delete(self.names, name)
+}
+
+// removeRoute returns the sibling list with the handlers for method, including every version,
+// at the literal path comps removed. Every node along the path is copied rather than modified,
+// and nodes left without handlers or children are dropped. The given slice must be owned by
+// the caller.
+func removeRoute[RouteState RouteStateCompatible](routes []*Route[RouteState], comps []string, method HttpMethod) ([]*Route[RouteState], []RouteHandler[RouteState], bool) {
+	idx := slices.IndexFunc(routes, func(route *Route[RouteState]) bool {
+		return route.PathComponent == comps[0]
+	})
+	if idx < 0 {
+		return routes, nil, false
+	}
+	node := routes[idx].clone()
+	var removed []RouteHandler[RouteState]
+	var found bool
+	if len(comps) == 1 {
+		var handler RouteHandler[RouteState]
+		handler, found = node.Handlers[method]
+		removed = append(slices.Collect(maps.Values(node.Versions[method])), handler)
+		delete(node.Handlers, method)
+		delete(node.Versions, method)
+		if len(node.Handlers) == 0 {
+			node.trailingSlash = false
+		}
+	} else {
+		node.Children, removed, found = removeRoute(node.Children, comps[1:], method)
+	}
+	if !found {
+		return routes, removed, false
+	}
+	if len(node.Handlers) == 0 && len(node.Children) == 0 {
+		return slices.Delete(routes, idx, idx+1), removed, true
+	}
+	routes[idx] = node
+	return routes, removed, true
+}
+
+// findLiteral returns the node at the literal path comps, comparing pattern components
+// as written rather than matching parameters, or nil if there is none.
+func findLiteral[RouteState RouteStateCompatible](routes []*Route[RouteState], comps []string) *Route[RouteState] {
+	var node *Route[RouteState]
+	for _, comp := range comps {
+		idx := slices.IndexFunc(routes, func(route *Route[RouteState]) bool {
+			return route.PathComponent == comp
+		})
+		if idx < 0 {
+			return nil
+		}
+		node = routes[idx]
+		routes = node.Children
+	}
+	return node
+}
@@ -0,0 +1,138 @@
+package pilot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRemoveRoute(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRouteHandler(Get, "/users/:id", RouteHandler[struct{}]{Handler: noopHandler, Name: "user.show"})
+	routes.AddRoute(Delete, "/users/:id", noopHandler)
+	routes.AddRoute(Get, "/users/:id/posts", noopHandler)
+
+	if err := routes.RemoveRoute(Get, "/users/:id"); err != nil {
+		t.Fatal(err)
+	}
+	route, _ := routes.MatchPath("/users/42")
+	if route == nil || len(route.Handlers) != 1 {
+		t.Fatalf("/users/42 = %v, want only the DELETE handler", route)
+	}
+	if _, err := routes.URL("user.show", "id", 42); err == nil {
+		t.Error("route name was not released")
+	}
+
+	routes.RemoveRoute(Delete, "/users/:id")
+	routes.RemoveRoute(Get, "/users/:id/posts")
+	if len(routes.Routes) != 0 {
+		t.Errorf("empty nodes were not pruned: %v", routes.List())
+	}
+	if err := routes.RemoveRoute(Get, "/users/:id"); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("removing a missing route = %v, want ErrRouteNotFound", err)
+	}
+}
+
+func TestReplaceRoute(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRouteHandler(Get, "/search", RouteHandler[struct{}]{
+		Name:    "search",
+		Handler: func(req *RouteRequest[struct{}]) *HttpResponse { return StringResponse("v1") },
+	})
+	before, _ := app.Routes.MatchPath("/search")
+
+	err := app.Routes.ReplaceRoute(Get, "/search", RouteHandler[struct{}]{
+		Handler: func(req *RouteRequest[struct{}]) *HttpResponse { return StringResponse("v2") },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := serveTestRequest(app, Get, "/search"); string(response.Body) != "v2" {
+		t.Errorf("GET /search = %q, want v2", response.Body)
+	}
+	if response := before.Handlers[Get].Handler(&RouteRequest[struct{}]{}); string(response.Body) != "v1" {
+		t.Errorf("previously matched route changed to %q", response.Body)
+	}
+	if _, err := app.URL("search"); err == nil {
+		t.Error("route name of the replaced handler was not released")
+	}
+	if err := app.Routes.AddRoute(Get, "/search", noopHandler); !errors.Is(err, ErrRouteConflict) {
+		t.Errorf("AddRoute after ReplaceRoute = %v, want ErrRouteConflict", err)
+	}
+}
+
+func TestUpdateIsAtomic(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRoute(Get, "/a", noopHandler)
+	routes.AddRoute(Get, "/b", noopHandler)
+
+	err := routes.Update(func(staging *RouteCollection[struct{}]) error {
+		staging.RemoveRoute(Get, "/a")
+		staging.AddRoute(Get, "/c", noopHandler)
+		return staging.AddRoute(Get, "/b", noopHandler)
+	})
+	if !errors.Is(err, ErrRouteConflict) {
+		t.Fatalf("Update = %v, want ErrRouteConflict", err)
+	}
+	for path, want := range map[string]bool{"/a": true, "/b": true, "/c": false} {
+		if route, _ := routes.MatchPath(path); (route != nil) != want {
+			t.Errorf("after failed Update, %s registered = %v, want %v", path, route != nil, want)
+		}
+	}
+
+	group := NewRouteGroup(GetRoute("/x", noopHandler), GetRoute("/a", noopHandler))
+	if err := routes.AddRouteGroup("/", group); !errors.Is(err, ErrRouteConflict) {
+		t.Fatalf("AddRouteGroup = %v, want ErrRouteConflict", err)
+	}
+	if route, _ := routes.MatchPath("/x"); route != nil {
+		t.Error("AddRouteGroup left a partially registered group")
+	}
+
+	group = NewRouteGroup(GetRoute("/x", noopHandler), GetRoute("/y", noopHandler))
+	routes.AddRouteGroup("/g", group)
+	if err := routes.RemoveRouteGroup("/g", group); err != nil {
+		t.Fatal(err)
+	}
+	if len(routes.List()) != 2 {
+		t.Errorf("routes after RemoveRouteGroup = %v", routes.List())
+	}
+}
+
+func TestConcurrentRegistration(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/stable/:id", noopHandler)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if response := serveTestRequest(app, Get, "/stable/1"); response.StatusCode != StatusOK {
+					t.Errorf("GET /stable/1 = %d during registration", response.StatusCode)
+					return
+				}
+				serveTestRequest(app, Get, "/flag/1")
+			}
+		}()
+	}
+	for i := range 200 {
+		path := fmt.Sprintf("/flag/%d", i%3)
+		if err := app.Routes.ReplaceRoute(Get, path, RouteHandler[struct{}]{Handler: noopHandler}); err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 {
+			app.Routes.RemoveRoute(Get, path)
+		}
+		app.Routes.List()
+	}
+	close(stop)
+	wg.Wait()
+}
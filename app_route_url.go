@@ -0,0 +1,162 @@
+package pilot
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+)
+
+// NameRoute gives a route name to the handler already registered for method and path, so
+// routes added with AddRoute or AddRouteWithMiddleware can be used with URL. Every version
+// of the handler gets the name, and a name it had before is released. AddRouteHandler
+// with RouteHandler.Name, or GroupedRoute.Named, names a route as it is registered.
+//
+// If Strict is set, errors panic instead of being returned.
+//
+// Parameters:
+//   - method: HTTP method of the registered handler
+//   - path: Route pattern the handler was registered with (e.g., "/users/:id")
+//   - name: Unique route name (e.g., "user.show")
+//
+// Returns:
+//   - error: A wrapped ErrRouteNotFound if no handler is registered for method and path,
+//     or a wrapped ErrRouteConflict if the name is used by a route with a different path
+//
+// Example:
+//
+//	routes.AddRoute(pilot.Get, "/users/:id", getUser)
+//	routes.NameRoute(pilot.Get, "/users/:id", "user.show")
+//	location, _ := routes.URL("user.show", "id", 42) // "/users/42"
+func (self *RouteCollection[RouteState]) NameRoute(method HttpMethod, path string, name string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	err := self.nameRoute(method, path, name)
+	if err != nil && self.Strict {
+		panic(err)
+	}
+	return err
+}
+
+// nameRoute implements NameRoute. The caller must hold the lock.
+func (self *RouteCollection[RouteState]) nameRoute(method HttpMethod, path string, name string) error {
+	node := findLiteral(self.Routes, PathListFromString(path))
+	if node == nil {
+		return fmt.Errorf("%w: %s %s", ErrRouteNotFound, method, path)
+	}
+	previous, found := node.Handlers[method]
+	if !found {
+		return fmt.Errorf("%w: %s %s", ErrRouteNotFound, method, path)
+	}
+	if existing, found := self.names[name]; found && existing != path {
+		return fmt.Errorf("%w: route name %q is already used by %q", ErrRouteConflict, name, existing)
+	}
+	node = self.writablePath(path)
+	handler := node.Handlers[method]
+	handler.Name = name
+	node.Handlers[method] = handler
+	if versions := node.Versions[method]; versions != nil {
+		versions = maps.Clone(versions)
+		for version, handler := range versions {
+			handler.Name = name
+			versions[version] = handler
+		}
+		node.Versions[method] = versions
+	}
+	if previous.Name != name {
+		self.forgetName(node, previous.Name)
+	}
+	if self.names == nil {
+		self.names = map[string]string{}
+	}
+	self.names[name] = path
+	return nil
+}
+
+// URL builds the path of a named route by substituting parameter values into its pattern.
+// This enables Location headers, redirects and hypermedia links that keep working when a
+// route's path or its group prefix changes, because callers only refer to the route name.
+//
+// Parameters are given as alternating name/value pairs. Values are formatted with fmt.Sprint,
+// so integers, UUIDs and other Stringers can be passed directly. Parameter values are
+// percent-encoded, and catch-all values keep their "/" separators. A trailing slash in the
+// registered pattern is kept, so the generated path matches the route under every
+// TrailingSlash policy.
+//
+// Parameters:
+//   - name: The route name given in RouteHandler.Name or GroupedRoute.Name
+//   - params: Alternating parameter names and values (e.g., "id", 42)
+//
+// Returns:
+//   - string: The generated path (e.g., "/users/42")
+//   - error: If the name is unknown, a parameter is missing or unused, or a value
+//     does not satisfy the parameter's constraint
+//
+// Example:
+//
+//	routes.AddRouteHandler(pilot.Get, "/users/{id:int}/files/*path", pilot.RouteHandler[AppState]{
+//	    Name:    "user.file",
+//	    Handler: getUserFile,
+//	})
+//	link, err := routes.URL("user.file", "id", 42, "path", "docs/cv.pdf")
+//	// link == "/users/42/files/docs/cv.pdf"
+func (self *RouteCollection[RouteState]) URL(name string, params ...any) (string, error) {
+	self.mu.Lock()
+	pattern, found := self.names[name]
+	self.mu.Unlock()
+	if !found {
+		return "", fmt.Errorf("pilot: no route named %q", name)
+	}
+	return buildURL(pattern, params)
+}
+
+// buildURL substitutes parameter values into a route pattern, validating that every
+// parameter is supplied exactly once and satisfies its constraint.
+func buildURL(pattern string, params []any) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("pilot: URL parameters for %q must be name/value pairs", pattern)
+	}
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		key, ok := params[i].(string)
+		if !ok {
+			return "", fmt.Errorf("pilot: URL parameter name %v for %q is not a string", params[i], pattern)
+		}
+		values[key] = fmt.Sprint(params[i+1])
+	}
+
+	var output strings.Builder
+	used := 0
+	for _, component := range PathListFromString(pattern) {
+		output.WriteString("/")
+		segment := parseSegment(component)
+		if segment.kind == segmentStatic {
+			output.WriteString(component)
+			continue
+		}
+		value, found := values[segment.name]
+		if !found {
+			return "", fmt.Errorf("pilot: missing URL parameter %q for %q", segment.name, pattern)
+		}
+		used++
+		if segment.kind == segmentCatchAll {
+			parts := strings.Split(value, "/")
+			for i := range parts {
+				parts[i] = url.PathEscape(parts[i])
+			}
+			output.WriteString(strings.Join(parts, "/"))
+			continue
+		}
+		if value == "" || (segment.matcher != nil && !segment.matcher(value)) {
+			return "", fmt.Errorf("pilot: URL parameter %q value %q does not match %q", segment.name, value, component)
+		}
+		output.WriteString(url.PathEscape(value))
+	}
+	if used != len(values) {
+		return "", fmt.Errorf("pilot: unused URL parameters for %q", pattern)
+	}
+	if hasTrailingSlash(pattern) {
+		output.WriteString("/")
+	}
+	return output.String(), nil
+}
@@ -0,0 +1,113 @@
+package pilot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRouteCollectionURL(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRouteHandler(Get, "/users/:id", RouteHandler[struct{}]{Name: "user.show", Handler: noopHandler})
+	routes.AddRouteHandler(Get, "/users/{id:int}/files/*path", RouteHandler[struct{}]{Name: "user.file", Handler: noopHandler})
+	routes.AddRouteHandler(Get, "/", RouteHandler[struct{}]{Name: "home", Handler: noopHandler})
+	routes.AddRouteHandler(Get, "/docs/:section/", RouteHandler[struct{}]{Name: "docs", Handler: noopHandler})
+
+	tests := []struct {
+		name   string
+		route  string
+		params []any
+		want   string
+	}{
+		{name: "param", route: "user.show", params: []any{"id", 42}, want: "/users/42"},
+		{name: "escaped param", route: "user.show", params: []any{"id", "a b/c"}, want: "/users/a%20b%2Fc"},
+		{name: "catch-all", route: "user.file", params: []any{"id", 7, "path", "docs/cv 1.pdf"}, want: "/users/7/files/docs/cv%201.pdf"},
+		{name: "root", route: "home", want: "/"},
+		{name: "trailing slash", route: "docs", params: []any{"section", "intro"}, want: "/docs/intro/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := routes.URL(tt.route, tt.params...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("URL(%q) = %q, want %q", tt.route, got, tt.want)
+			}
+		})
+	}
+
+	failures := []struct {
+		name   string
+		route  string
+		params []any
+	}{
+		{name: "unknown route", route: "user.delete", params: []any{"id", 1}},
+		{name: "missing param", route: "user.show"},
+		{name: "unused param", route: "user.show", params: []any{"id", 1, "format", "json"}},
+		{name: "odd params", route: "user.show", params: []any{"id"}},
+		{name: "constraint", route: "user.file", params: []any{"id", "abc", "path", "x"}},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := routes.URL(tt.route, tt.params...); err == nil {
+				t.Errorf("URL(%q) = %q, want error", tt.route, got)
+			}
+		})
+	}
+}
+
+func TestApplicationURLWithGroupPrefix(t *testing.T) {
+	app := newTestApplication()
+	app.AddRouteGroup("/api", NewRouteGroup(
+		GetRoute("/users/:id", noopHandler).Named("user.show"),
+	))
+	got, err := app.URL("user.show", "id", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/api/users/42" {
+		t.Errorf("URL = %q, want %q", got, "/api/users/42")
+	}
+}
+
+func TestURLTrailingSlashPolicy(t *testing.T) {
+	for _, policy := range []TrailingSlashPolicy{TrailingSlashStrict, TrailingSlashRedirect} {
+		app := newTestApplication()
+		app.TrailingSlash = policy
+		app.Routes.AddRouteHandler(Get, "/docs/", RouteHandler[struct{}]{Name: "docs", Handler: noopHandler})
+		link, err := app.URL("docs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response := serveTestRequest(app, Get, link); response.StatusCode != StatusOK {
+			t.Errorf("policy %v: GET %s = %d, want 200", policy, link, response.StatusCode)
+		}
+	}
+}
+
+func TestNameRoute(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRoute(Get, "/users/:id", noopHandler)
+	routes.AddRouteWithMiddleware(Get, "/posts/:id", noopHandler, nil)
+	if err := routes.NameRoute(Get, "/users/:id", "user.show"); err != nil {
+		t.Fatal(err)
+	}
+	if err := routes.NameRoute(Get, "/posts/:id", "post.show"); err != nil {
+		t.Fatal(err)
+	}
+	if link, err := routes.URL("user.show", "id", 42); err != nil || link != "/users/42" {
+		t.Errorf("URL(user.show) = %q, %v", link, err)
+	}
+	if err := routes.NameRoute(Get, "/users/:id", "user.get"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := routes.URL("user.show", "id", 42); err == nil {
+		t.Errorf("renamed route kept its old name: %v", err)
+	}
+	if err := routes.NameRoute(Get, "/posts/:id", "user.get"); !errors.Is(err, ErrRouteConflict) {
+		t.Errorf("reused name = %v, want ErrRouteConflict", err)
+	}
+	if err := routes.NameRoute(Post, "/users/:id", "user.create"); !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("unregistered method = %v, want ErrRouteNotFound", err)
+	}
+}
@@ -1,8 +1,17 @@
 package pilot
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
 )
 
 // String returns the string representation of an HttpMethod for logging and debugging.
@@ -15,6 +24,18 @@ func (m HttpMethod) String() string {
 // data structure for efficient path matching. The collection supports dynamic route registration,
 // middleware attachment, and hierarchical route organization.
 //
+// The trie is the registration structure; lookups use a compressed radix index that is
+// compiled from it lazily (see MatchPath). Routes should be registered through the
+// collection's methods rather than by modifying Routes or Children directly, so the
+// index is invalidated correctly.
+//
+// The collection is safe for concurrent use, so routes can be added, replaced and removed
+// while the application is serving requests. Registrations are serialized by a mutex and
+// copy every trie node they modify instead of changing it in place, so a published index
+// and the nodes it points to are never mutated. Requests keep using the previous index
+// until the next lookup after a change compiles a new one. Use Update to apply several
+// changes atomically.
+//
 // The trie structure enables:
 //   - O(path_length) route lookup time regardless of route count
 //   - Support for path parameters (e.g., "/users/:id")
@@ -26,10 +47,29 @@ func (m HttpMethod) String() string {
 //
 // Fields:
 //   - Routes: Root-level route nodes forming the base of the routing trie
+//   - Strict: When true, registration errors panic instead of being returned,
+//     so a conflicting route table fails loudly at startup
 type RouteCollection[RouteState RouteStateCompatible] struct {
 	Routes []*Route[RouteState]
+	Strict bool
+	names  map[string]string
+	mu     sync.Mutex
+	index  atomic.Pointer[radixNode[RouteState]]
 }
 
+// ErrRouteConflict is returned (wrapped) when a registration would overwrite an existing
+// handler, reuse a route name for a different path, or add a parameter segment that can
+// never be reached because an equivalent sibling parameter already exists.
+var ErrRouteConflict = errors.New("pilot: route conflict")
+
+// ErrRouteNotFound is returned (wrapped) when removing a route that is not registered.
+var ErrRouteNotFound = errors.New("pilot: route not found")
+
+// ErrInvalidRoute is returned (wrapped) when a route pattern cannot be registered, such as
+// a regular expression constraint that does not compile or a catch-all segment that is not
+// the last component of the pattern.
+var ErrInvalidRoute = errors.New("pilot: invalid route")
+
 // NewRouteCollection creates an empty route collection ready for route registration.
 // This constructor initializes the routing trie structure that will efficiently
 // match incoming requests to their appropriate handlers.
@@ -45,6 +85,7 @@ type RouteCollection[RouteState RouteStateCompatible] struct {
 func NewRouteCollection[RouteState RouteStateCompatible]() *RouteCollection[RouteState] {
 	return &RouteCollection[RouteState]{
 		Routes: []*Route[RouteState]{},
+		names:  map[string]string{},
 	}
 }
 
@@ -62,6 +103,8 @@ func NewRouteCollection[RouteState RouteStateCompatible]() *RouteCollection[Rout
 // This is automatically called during application startup unless SilentMode is enabled,
 // providing immediate feedback about registered routes and helping identify routing conflicts.
 func (self *RouteCollection[RouteState]) PrintTree() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
 	for i := range self.Routes {
 		self.Routes[i].PrintTree(0)
 	}
@@ -74,13 +117,20 @@ func (self *RouteCollection[RouteState]) PrintTree() {
 //
 // The algorithm:
 //  1. Splits the path into components (e.g., "/users/profile" -> ["users", "profile"])
-//  2. Traverses the trie matching each component against existing nodes
-//  3. Returns the exact matching node or nil if not found (when create=false)
-//  4. Creates missing nodes along the path when create=true
+//  2. When create=true, walks the trie comparing components literally and creates
+//     missing nodes along the path, so "/users/:id" always resolves to the ":id" node;
+//     this also discards the compiled lookup index so it is rebuilt on the next lookup.
+//     Nodes along the path are replaced with copies, and the returned node must not be
+//     modified directly while requests are being served; use AddRouteHandler instead.
+//     A pattern with a component that does not compile, such as an invalid regular
+//     expression constraint, creates nothing and returns nil
+//  3. When create=false, delegates to MatchPath, which resolves parameter segments
+//     and only returns nodes that have at least one handler registered
 //
 // Path Parameter Support:
 // Supports path parameters using colon syntax (e.g., "/users/:id") where ":id"
-// becomes a wildcard component that matches any value in that path segment.
+// becomes a wildcard component that matches any non-empty value in that path segment.
+// Use MatchPath instead when the captured parameter values are needed.
 //
 // Parameters:
 //   - path: URL path to find or create (e.g., "/api/users/profile")
@@ -88,6 +138,7 @@ func (self *RouteCollection[RouteState]) PrintTree() {
 //
 // Returns:
 //   - *Route[RouteState]: The route node for the path, or nil if not found and create=false
+//     or if the pattern is invalid
 //
 // Example:
 //
@@ -99,45 +150,201 @@ func (self *RouteCollection[RouteState]) PrintTree() {
 //
 //	// Create route if missing
 //	route = routes.FindPath("/users/settings", true)
-//	// route is guaranteed to be non-nil
+//	// route is non-nil for any valid pattern
 func (self *RouteCollection[RouteState]) FindPath(path string, create bool) *Route[RouteState] {
-	comps := PathListFromString(path)
-	var node *Route[RouteState] = nil
-	for i := range self.Routes {
-		if self.Routes[i].PathComponent == comps[0] {
-			node = self.Routes[i]
-			break
+	if !create {
+		node, _ := self.MatchPath(path)
+		return node
+	}
+	for _, comp := range PathListFromString(path) {
+		if _, err := compileSegment(comp); err != nil {
+			return nil
 		}
 	}
-	if node == nil {
-		if create {
-			newNode := NewEmptyRoute[RouteState](comps[0])
-			self.Routes = append(self.Routes, &newNode)
-			node = &newNode
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.writablePath(path)
+}
+
+// writablePath returns the node for path, creating missing nodes and replacing every node
+// along the path with a copy, so the returned node can be modified without affecting
+// lookups that still use the published index. The caller must hold the lock.
+func (self *RouteCollection[RouteState]) writablePath(path string) *Route[RouteState] {
+	self.index.Store(nil)
+	siblings := &self.Routes
+	var node *Route[RouteState]
+	for _, comp := range PathListFromString(path) {
+		idx := slices.IndexFunc(*siblings, func(route *Route[RouteState]) bool {
+			return route.PathComponent == comp
+		})
+		if idx < 0 {
+			newRoute := NewEmptyRoute[RouteState](comp)
+			node = &newRoute
+			*siblings = append(*siblings, node)
 		} else {
-			return nil
+			node = (*siblings)[idx].clone()
+			(*siblings)[idx] = node
 		}
+		siblings = &node.Children
 	}
-	i := 1
-	for i < len(comps) {
-		foundAtDepth := false
-		for childIdx := range node.Children {
-			if node.Children[childIdx].PathComponent == comps[i] {
-				node = node.Children[childIdx]
-				foundAtDepth = true
-				break
+	return node
+}
+
+// clone returns a shallow copy of the node with its own Handlers and Versions maps and
+// Children slice. The per-method version maps are still shared and must be copied before
+// they are modified.
+func (self *Route[RouteState]) clone() *Route[RouteState] {
+	route := *self
+	route.Handlers = maps.Clone(self.Handlers)
+	route.Versions = maps.Clone(self.Versions)
+	route.Children = slices.Clone(self.Children)
+	return &route
+}
+
+// MatchPath resolves a request path against the routing trie and captures the values
+// of any parameter segments along the way. This is the lookup used for request dispatch.
+//
+// Matching rules:
+//   - Static components always take priority over parameter components at the same depth
+//   - Parameter components (":name" or "{name}") match any non-empty path segment
+//   - Constrained parameters ("{id:int}", "{id:uuid}", "{slug:[a-z-]+}") only match
+//     segments that satisfy the constraint and are tried before unconstrained ones
+//   - Catch-all components ("*name") match all remaining segments, including none,
+//     and capture them joined with "/" (e.g. "css/site.css"); they are tried last
+//   - If a static branch fails deeper in the path, matching backtracks and tries the
+//     parameter branches, so "/users/new" and "/users/:id/edit" can coexist
+//   - Only nodes with at least one registered handler are considered a match
+//
+// Lookups run against a compressed radix index compiled from the trie on first use after
+// a registration, so they do not split the path or allocate except for the returned
+// parameters; use MatchPathAppend to capture them into a reusable buffer instead.
+//
+// Parameters:
+//   - path: Request path to resolve (e.g., "/users/42")
+//
+// Returns:
+//   - *Route[RouteState]: The matched route node, or nil if nothing matches
+//   - PathParams: Parameter values captured while matching, in path order
+//
+// Example:
+//
+//	routes.AddRoute(pilot.Get, "/users/:id", getUser)
+//	route, params := routes.MatchPath("/users/42")
+//	id, _ := params.Get("id") // "42"
+func (self *RouteCollection[RouteState]) MatchPath(path string) (*Route[RouteState], PathParams) {
+	return self.MatchPathAppend(path, PathParams{})
+}
+
+// MatchPathAppend is MatchPath with the captured parameters appended to params, so a
+// caller that passes a buffer with enough spare capacity matches any path, including
+// ones with parameters, without allocating. Requests are routed this way with a buffer
+// held by the HttpRequest.
+//
+// Parameters:
+//   - path: Request path to resolve (e.g., "/users/42")
+//   - params: Buffer the captured parameters are appended to
+//
+// Returns:
+//   - *Route[RouteState]: The matched route node, or nil if nothing matches
+//   - PathParams: params with the captured values appended, or nil if nothing matches
+//
+// Example:
+//
+//	buffer := make(pilot.PathParams, 0, 4)
+//	route, params := routes.MatchPathAppend("/users/42", buffer[:0])
+func (self *RouteCollection[RouteState]) MatchPathAppend(path string, params PathParams) (*Route[RouteState], PathParams) {
+	node, params := self.lookup(path, params)
+	if node == nil {
+		return nil, nil
+	}
+	return node, params
+}
+
+// matchTrie resolves a path by walking the segment trie directly. It implements the same
+// rules as MatchPath without the compiled index, and is kept as the reference
+// implementation the radix index is tested and benchmarked against.
+func (self *RouteCollection[RouteState]) matchTrie(path string) (*Route[RouteState], PathParams) {
+	comps := PathListFromString(path)
+	params := PathParams{}
+	node := matchRoutes(self.Routes, comps, &params)
+	if node == nil {
+		return nil, nil
+	}
+	return node, params
+}
+
+// matchRoutes finds the first route among the sibling nodes that matches the given
+// path components, trying static nodes before parameter nodes and backtracking
+// whenever a branch fails to produce a node with handlers.
+func matchRoutes[RouteState RouteStateCompatible](nodes []*Route[RouteState], comps []string, params *PathParams) *Route[RouteState] {
+	for i := range nodes {
+		if nodes[i].segment.kind == segmentStatic && nodes[i].PathComponent == comps[0] {
+			if found := nodes[i].match(comps, params); found != nil {
+				return found
 			}
 		}
-		if !foundAtDepth && create {
-			newRoute := NewEmptyRoute[RouteState](comps[i])
-			node.Children = append(node.Children, &newRoute)
-			node = &newRoute
-		} else if !foundAtDepth {
-			return nil
+	}
+	if comps[0] != "" {
+		if found := matchParams(nodes, comps, params, true); found != nil {
+			return found
+		}
+		if found := matchParams(nodes, comps, params, false); found != nil {
+			return found
 		}
-		i++
 	}
-	return node
+	return matchCatchAll(nodes, strings.Join(comps, "/"), params)
+}
+
+// matchParams tries the parameter nodes among the siblings against comps[0], limited to
+// either constrained or unconstrained parameters so that typed parameters are always
+// attempted before untyped ones. Constraint checks happen here, during the lookup,
+// so a value that fails a constraint falls through to the next candidate.
+func matchParams[RouteState RouteStateCompatible](nodes []*Route[RouteState], comps []string, params *PathParams, constrained bool) *Route[RouteState] {
+	for i := range nodes {
+		segment := &nodes[i].segment
+		if segment.kind != segmentParam || (segment.matcher != nil) != constrained {
+			continue
+		}
+		if segment.matcher != nil && !segment.matcher(comps[0]) {
+			continue
+		}
+		mark := len(*params)
+		*params = append(*params, PathParam{Name: segment.name, Value: comps[0]})
+		if found := nodes[i].match(comps, params); found != nil {
+			return found
+		}
+		*params = (*params)[:mark]
+	}
+	return nil
+}
+
+// matchCatchAll returns the first catch-all node among the siblings that has handlers,
+// recording the joined remainder of the path as its parameter value.
+func matchCatchAll[RouteState RouteStateCompatible](nodes []*Route[RouteState], rest string, params *PathParams) *Route[RouteState] {
+	for i := range nodes {
+		if nodes[i].segment.kind == segmentCatchAll && len(nodes[i].Handlers) > 0 {
+			*params = append(*params, PathParam{Name: nodes[i].segment.name, Value: rest})
+			return nodes[i]
+		}
+	}
+	return nil
+}
+
+// match continues a lookup once this node has consumed comps[0], returning the
+// terminal node for the remaining components or nil if none of them match.
+// A catch-all node consumes every remaining component, and a catch-all child
+// also matches when the path ends exactly at this node.
+func (self *Route[RouteState]) match(comps []string, params *PathParams) *Route[RouteState] {
+	if self.segment.kind == segmentCatchAll {
+		return self
+	}
+	if len(comps) == 1 {
+		if len(self.Handlers) > 0 {
+			return self
+		}
+		return matchCatchAll(self.Children, "", params)
+	}
+	return matchRoutes(self.Children, comps[1:], params)
 }
 
 // AddRoute registers a route handler for the specified HTTP method and path without middleware.
@@ -145,23 +352,28 @@ func (self *RouteCollection[RouteState]) FindPath(path string, create bool) *Rou
 // with an empty middleware slice.
 //
 // The method automatically creates the route path in the trie structure if it doesn't exist,
-// then associates the handler with the specified HTTP method for that path.
+// then associates the handler with the specified HTTP method for that path. To register a
+// named route, use AddRouteHandler with RouteHandler.Name, or name it afterwards with NameRoute.
 //
 // Parameters:
 //   - method: HTTP method this handler responds to (Get, Post, Put, etc.)
-//   - path: URL path pattern (e.g., "/users", "/users/:id", "/api/posts")
+//   - path: URL path pattern (e.g., "/users", "/users/:id", "/static/*filepath")
 //   - fn: Handler function that processes requests to this endpoint
 //
+// Returns:
+//   - error: A wrapped ErrRouteConflict or ErrInvalidRoute if the route cannot be
+//     registered; see AddRouteHandler
+//
 // Example:
 //
 //	routes.AddRoute(pilot.Get, "/users", func(req *pilot.RouteRequest[AppState]) *pilot.HttpResponse {
 //	    return pilot.JsonResponse([]User{})
 //	})
-func (self *RouteCollection[RouteState]) AddRoute(method HttpMethod, path string, fn RouteHandlerFn[RouteState]) {
-	self.FindPath(path, true).Handlers[method] = RouteHandler[RouteState]{
+func (self *RouteCollection[RouteState]) AddRoute(method HttpMethod, path string, fn RouteHandlerFn[RouteState]) error {
+	return self.AddRouteHandler(method, path, RouteHandler[RouteState]{
 		Handler:    fn,
 		Middleware: []MiddlewareFn[RouteState]{},
-	}
+	})
 }
 
 // AddRouteWithMiddleware registers a route handler with associated middleware functions.
@@ -180,27 +392,197 @@ func (self *RouteCollection[RouteState]) AddRoute(method HttpMethod, path string
 //   - Rate limiting with immediate error responses
 //   - Logging and monitoring of request processing
 //
+// Like AddRoute, it registers an unnamed route; see NameRoute.
+//
 // Parameters:
 //   - method: HTTP method this handler responds to
 //   - path: URL path pattern supporting parameters (e.g., "/users/:id")
 //   - fn: Main handler function executed after all middleware passes
 //   - middleware: Slice of middleware functions executed before the handler
 //
+// Returns:
+//   - error: A wrapped ErrRouteConflict or ErrInvalidRoute if the route cannot be
+//     registered; see AddRouteHandler
+//
 // Example:
 //
 //	authMiddleware := func(req *pilot.RouteRequest[AppState]) *pilot.HttpResponse {
-//	    if req.Request.GetHeader("Authorization") == "" {
+//	    if req.Request.Headers.Get("Authorization") == "" {
 //	        return pilot.UnauthorizedResponse("Missing auth token")
 //	    }
 //	    return nil // Continue to handler
 //	}
 //
 //	routes.AddRouteWithMiddleware(pilot.Get, "/admin/users", adminHandler, []MiddlewareFn[AppState]{authMiddleware})
-func (self *RouteCollection[RouteState]) AddRouteWithMiddleware(method HttpMethod, path string, fn RouteHandlerFn[RouteState], middleware []MiddlewareFn[RouteState]) {
-	self.FindPath(path, true).Handlers[method] = RouteHandler[RouteState]{
+func (self *RouteCollection[RouteState]) AddRouteWithMiddleware(method HttpMethod, path string, fn RouteHandlerFn[RouteState], middleware []MiddlewareFn[RouteState]) error {
+	return self.AddRouteHandler(method, path, RouteHandler[RouteState]{
 		Handler:    fn,
 		Middleware: middleware,
+	})
+}
+
+// AddRouteHandler registers a fully configured RouteHandler for the specified HTTP method and path.
+// AddRoute and AddRouteWithMiddleware are shorthands for this method; use it directly when the
+// registration needs more than a handler and middleware, such as a route name.
+//
+// When handler.Name is set, the route's path pattern is recorded under that name so URLs
+// can later be generated with URL.
+//
+// Registration is rejected, leaving the collection unchanged, when:
+//   - A handler is already registered for the same method and path (ErrRouteConflict)
+//   - The name is already used by a route with a different path (ErrRouteConflict)
+//   - A parameter segment is equivalent to an existing sibling with a different name,
+//     e.g. "/users/:id" and "/users/{key}", so one of them could never match (ErrRouteConflict)
+//   - The path differs in its trailing slash from other handlers registered for the same
+//     pattern, e.g. "/docs/" and "/docs", since the TrailingSlash policy needs a single
+//     registered form per path (ErrRouteConflict)
+//   - The pattern is malformed, such as a catch-all that is not the last component or
+//     a constraint that does not compile (ErrInvalidRoute)
+//   - The method is neither a standard method nor registered with RegisterMethod (ErrInvalidRoute)
+//
+// If Strict is set, these errors panic instead of being returned.
+//
+// Parameters:
+//   - method: HTTP method this handler responds to
+//   - path: URL path pattern supporting parameters (e.g., "/users/:id")
+//   - handler: Handler, middleware and registration options for this route
+//
+// Returns:
+//   - error: Why the route could not be registered, or nil on success
+//
+// Example:
+//
+//	routes.AddRouteHandler(pilot.Get, "/users/:id", pilot.RouteHandler[AppState]{
+//	    Name:    "user.show",
+//	    Handler: getUser,
+//	})
+//	location, _ := routes.URL("user.show", "id", 42) // "/users/42"
+func (self *RouteCollection[RouteState]) AddRouteHandler(method HttpMethod, path string, handler RouteHandler[RouteState]) error {
+	return self.setRouteHandler(method, path, handler, false)
+}
+
+// setRouteHandler registers or, if replace is set, replaces the handler for a method and path.
+func (self *RouteCollection[RouteState]) setRouteHandler(method HttpMethod, path string, handler RouteHandler[RouteState], replace bool) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if err := self.checkRegistration(method, path, handler, replace); err != nil {
+		if self.Strict {
+			panic(err)
+		}
+		return err
+	}
+	node := self.writablePath(path)
+	var previous RouteHandler[RouteState]
+	var replaced bool
+	if handler.Version == "" {
+		previous, replaced = node.Handlers[method]
+		replaced = replaced && previous.Version == ""
+		node.Handlers[method] = handler
+	} else {
+		versions := maps.Clone(node.Versions[method])
+		if versions == nil {
+			versions = map[string]RouteHandler[RouteState]{}
+		}
+		previous, replaced = versions[handler.Version]
+		versions[handler.Version] = handler
+		if node.Versions == nil {
+			node.Versions = map[HttpMethod]map[string]RouteHandler[RouteState]{}
+		}
+		node.Versions[method] = versions
+		if primary, found := node.Handlers[method]; !found || primary.Version == handler.Version {
+			node.Handlers[method] = handler
+		}
+	}
+	node.trailingSlash = hasTrailingSlash(path)
+	if replaced && previous.Name != handler.Name {
+		self.forgetName(node, previous.Name)
+	}
+	if handler.Name != "" {
+		if self.names == nil {
+			self.names = map[string]string{}
+		}
+		self.names[handler.Name] = path
+	}
+	return nil
+}
+
+// checkRegistration validates a route pattern and walks the existing trie, without
+// modifying it, to detect duplicate handlers, reused names and ambiguous parameters.
+// Existing handlers for the method and version are only reported when replace is false.
+func (self *RouteCollection[RouteState]) checkRegistration(method HttpMethod, path string, handler RouteHandler[RouteState], replace bool) error {
+	name := handler.Name
+	if existing, found := self.names[name]; found && name != "" && existing != path {
+		return fmt.Errorf("%w: route name %q is already used by %q", ErrRouteConflict, name, existing)
+	}
+	if !method.isImplemented() {
+		return fmt.Errorf("%w: %s %s: method %q is not registered, see RegisterMethod", ErrInvalidRoute, method, path, method)
+	}
+	comps := PathListFromString(path)
+	segments := make([]routeSegment, len(comps))
+	for i := range comps {
+		segment, err := compileSegment(comps[i])
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidRoute, path, err)
+		}
+		if segment.kind == segmentCatchAll && i != len(comps)-1 {
+			return fmt.Errorf("%w: %s: catch-all %q must be the last path component", ErrInvalidRoute, path, comps[i])
+		}
+		segments[i] = segment
+	}
+
+	siblings := self.Routes
+	var node *Route[RouteState]
+	for i := range comps {
+		node = nil
+		for _, sibling := range siblings {
+			if sibling.PathComponent == comps[i] {
+				node = sibling
+				break
+			}
+		}
+		if node == nil {
+			for _, sibling := range siblings {
+				if segments[i].kind != segmentStatic && sibling.segment.kind == segments[i].kind && sibling.segment.constraint == segments[i].constraint {
+					return fmt.Errorf("%w: %s %s: %q is ambiguous with existing %q", ErrRouteConflict, method, path, comps[i], sibling.PathComponent)
+				}
+			}
+			return nil
+		}
+		siblings = node.Children
+	}
+	if node.slashConflict(method, path, handler.Version, replace) {
+		return fmt.Errorf("%w: %s %s: other handlers for the path are registered with a different trailing slash", ErrRouteConflict, method, path)
+	}
+	if replace {
+		return nil
+	}
+	if _, found := node.Versions[method][handler.Version]; found && handler.Version != "" {
+		return fmt.Errorf("%w: %s %s version %q is already registered", ErrRouteConflict, method, path, handler.Version)
+	}
+	if existing, found := node.Handlers[method]; found && handler.Version == "" && existing.Version == "" {
+		return fmt.Errorf("%w: %s %s is already registered", ErrRouteConflict, method, path)
+	}
+	return nil
+}
+
+// slashConflict reports whether registering path on this node would change the trailing
+// slash form its other handlers were registered with. A handler being replaced does not
+// count, so ReplaceRoute can change the form of a path's only handler.
+func (self *Route[RouteState]) slashConflict(method HttpMethod, path string, version string, replace bool) bool {
+	if hasTrailingSlash(path) == self.trailingSlash {
+		return false
+	}
+	for other, existing := range self.Handlers {
+		if other != method || !replace || existing.Version != version {
+			return true
+		}
+	}
+	for other := range self.Versions[method] {
+		if other != version {
+			return true
+		}
 	}
+	return false
 }
 
 // RouteHandlerFn defines the signature for route handler functions that process HTTP requests.
@@ -257,16 +639,103 @@ type MiddlewareFn[RouteState RouteStateCompatible] func(*RouteRequest[RouteState
 //
 // Fields:
 //   - PathComponent: The URL segment this route matches (e.g., "users", ":id", "profile")
-//   - Handlers: Map of HTTP methods to their corresponding handler and middleware
+//   - Handlers: Map of HTTP methods to their corresponding handler and middleware; for
+//     versioned methods this is the unversioned handler, or the first registered version
+//   - Versions: Versioned handlers by HTTP method and version, see RouteHandler.Version
 //   - Children: Child route nodes for deeper path segments
 //
 // Path Parameters:
 // Components starting with ":" are treated as parameters that match any value.
-// The matched value becomes available in the request context.
+// Components wrapped in braces are parameters with an optional type or regular
+// expression constraint (e.g., "{id:int}", "{slug:[a-z0-9-]+}") that is checked
+// during lookup. Components starting with "*" are catch-all parameters that must
+// be the last component of a pattern and match the whole remainder of the path.
+// The matched value becomes available through HttpRequest.GetParam.
 type Route[RouteState RouteStateCompatible] struct {
 	PathComponent string
-	Handlers      map[HttpMethod]RouteHandler[RouteState] `json:"-"`
+	Handlers      map[HttpMethod]RouteHandler[RouteState]            `json:"-"`
+	Versions      map[HttpMethod]map[string]RouteHandler[RouteState] `json:"-"`
 	Children      []*Route[RouteState]
+	segment       routeSegment
+	trailingSlash bool
+}
+
+// segmentKind classifies how a route node's path component is matched against
+// an incoming path segment.
+type segmentKind int
+
+const (
+	segmentStatic segmentKind = iota
+	segmentParam
+	segmentCatchAll
+)
+
+// routeSegment is the parsed form of a route node's path component, computed once
+// at registration time so lookups never need to re-inspect the component string.
+type routeSegment struct {
+	kind       segmentKind
+	name       string
+	constraint string
+	matcher    func(string) bool
+}
+
+// ParamMatchers maps the type names usable in constrained parameters ("{id:int}")
+// to the functions that validate a path segment for that type. Any constraint that
+// is not a key in this map is compiled as a regular expression that must match the
+// whole segment. Additional types can be registered before routes are added:
+//
+//	pilot.ParamMatchers["slug"] = func(s string) bool { return slugPattern.MatchString(s) }
+var ParamMatchers = map[string]func(string) bool{
+	"int": func(value string) bool {
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	},
+	"uuid": func(value string) bool {
+		_, err := uuid.Parse(value)
+		return err == nil
+	},
+}
+
+// compileSegment classifies a single path component from a route pattern.
+// Components of the form ":name" or "{name}" become parameter segments, "{name:type}"
+// and "{name:regex}" become constrained parameter segments, "*name" becomes a
+// catch-all segment, and everything else is matched literally.
+//
+// Returns an error if a regular expression constraint does not compile.
+func compileSegment(component string) (routeSegment, error) {
+	if len(component) > 1 && component[0] == ':' {
+		return routeSegment{kind: segmentParam, name: component[1:]}, nil
+	}
+	if len(component) > 1 && component[0] == '*' {
+		return routeSegment{kind: segmentCatchAll, name: component[1:]}, nil
+	}
+	if len(component) > 2 && component[0] == '{' && component[len(component)-1] == '}' {
+		inner := component[1 : len(component)-1]
+		name, constraint, found := strings.Cut(inner, ":")
+		if !found || constraint == "" {
+			return routeSegment{kind: segmentParam, name: name}, nil
+		}
+		matcher, ok := ParamMatchers[constraint]
+		if !ok {
+			pattern, err := regexp.Compile("^(?:" + constraint + ")$")
+			if err != nil {
+				return routeSegment{}, err
+			}
+			matcher = pattern.MatchString
+		}
+		return routeSegment{kind: segmentParam, name: name, constraint: constraint, matcher: matcher}, nil
+	}
+	return routeSegment{kind: segmentStatic}, nil
+}
+
+// parseSegment is compileSegment for components that are already known to be valid,
+// and panics if one is not.
+func parseSegment(component string) routeSegment {
+	segment, err := compileSegment(component)
+	if err != nil {
+		panic(err)
+	}
+	return segment
 }
 
 // RouteHandler combines a handler function with its associated middleware pipeline.
@@ -282,9 +751,33 @@ type Route[RouteState RouteStateCompatible] struct {
 // Fields:
 //   - Handler: The main function that processes the request after middleware
 //   - Middleware: Slice of functions executed before the handler, in order
+//   - Name: Optional unique name used to generate the route's URL with URL
+//   - Metadata: Optional description of the route, available to middleware as
+//     RouteRequest.Metadata and listed by RouteCollection.List
+//   - Version: Optional API version this handler serves; several versions of the same
+//     method and path can be registered side by side, see Application.VersionHeader
+//   - Limits: Optional request size limits replacing the application's limits for this
+//     handler where they are non-zero, see RequestLimits
+//
+// Versioned handlers are selected by the version the request names, falling back to the
+// unversioned handler if one is registered. Without a requested or default version, the
+// unversioned handler, or else the first registered version, serves the request, so
+// clients that never send a version keep getting the original behavior:
+//
+//	routes.AddRoute(pilot.Get, "/users/:id", getUserV1)
+//	routes.AddRouteHandler(pilot.Get, "/users/:id", pilot.RouteHandler[AppState]{
+//	    Handler: getUserV2,
+//	    Version: "2",
+//	})
+//	// GET /users/1                      → getUserV1
+//	// GET /users/1 (Accept-Version: 2)  → getUserV2
 type RouteHandler[RouteState RouteStateCompatible] struct {
 	Handler    RouteHandlerFn[RouteState]
 	Middleware []MiddlewareFn[RouteState]
+	Name       string
+	Metadata   RouteMetadata
+	Version    string
+	Limits     RequestLimits
 }
 
 // PrintTree recursively prints this route and all child routes in a hierarchical tree format.
@@ -319,9 +812,61 @@ func (self *Route[RouteState]) PrintTree(level int) {
 	}
 }
 
+// AllowedMethods returns the HTTP methods this route responds to, sorted alphabetically.
+// OPTIONS is always included because preflight requests are answered automatically
+// for any route that has handlers, and HEAD is included whenever GET is, since HEAD
+// requests fall back to the GET handler. The result is used for the Allow header on
+// 405 Method Not Allowed and OPTIONS responses.
+//
+// Returns:
+//   - []string: Method names such as ["DELETE", "GET", "HEAD", "OPTIONS"]
+func (self *Route[RouteState]) AllowedMethods() []string {
+	methods := make([]string, 0, len(self.Handlers)+2)
+	for k := range self.Handlers {
+		methods = append(methods, string(k))
+	}
+	if _, found := self.Handlers[Options]; !found {
+		methods = append(methods, string(Options))
+	}
+	_, hasGet := self.Handlers[Get]
+	if _, found := self.Handlers[Head]; !found && hasGet {
+		methods = append(methods, string(Head))
+	}
+	slices.Sort(methods)
+	return methods
+}
+
+// handlerFor returns the handler registered for the method, falling back to the
+// GET handler for HEAD requests when no HEAD handler was registered explicitly.
+func (self *Route[RouteState]) handlerFor(method HttpMethod) (RouteHandler[RouteState], bool) {
+	handler, found := self.Handlers[method]
+	if !found && method == Head {
+		handler, found = self.Handlers[Get]
+	}
+	return handler, found
+}
+
+// versionFor selects the handler for an API version among the versions registered for
+// the method, given the handler handlerFor returned. A version without a dedicated
+// handler is served by the unversioned handler; otherwise, an explicitly requested
+// version is not acceptable, while a default version falls back to the primary handler.
+func (self *Route[RouteState]) versionFor(method HttpMethod, primary RouteHandler[RouteState], version string, explicit bool) (RouteHandler[RouteState], bool) {
+	if _, found := self.Handlers[method]; !found && method == Head {
+		method = Get
+	}
+	if handler, found := self.Versions[method][version]; found && version != "" {
+		return handler, true
+	}
+	if primary.Version == "" || !explicit {
+		return primary, true
+	}
+	return RouteHandler[RouteState]{}, false
+}
+
 // NewEmptyRoute creates a new route node with no handlers or children for the specified path component.
 // This constructor is used internally by the routing system when building the trie structure.
-// The created route is ready to have handlers and child routes added to it.
+// The created route is ready to have handlers and child routes added to it. It panics if the
+// component is a parameter whose regular expression constraint does not compile.
 //
 // Parameters:
 //   - path: The path component this route will match (e.g., "users", ":id", "admin")
@@ -339,5 +884,6 @@ func NewEmptyRoute[RouteState RouteStateCompatible](path string) Route[RouteStat
 		PathComponent: path,
 		Handlers:      map[HttpMethod]RouteHandler[RouteState]{},
 		Children:      []*Route[RouteState]{},
+		segment:       parseSegment(path),
 	}
 }
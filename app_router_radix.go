@@ -0,0 +1,164 @@
+package pilot
+
+import "strings"
+
+// radixNode is one edge of the compiled lookup index built from a RouteCollection's trie.
+// Chains of static trie nodes that have no handlers and a single static child are merged
+// into one node whose label spans several path segments (e.g., "api/v1/users"), so common
+// prefixes are compared in a single step instead of one trie level per segment.
+//
+// Children are pre-sorted by matching priority: static children are indexed by their first
+// segment, followed by constrained parameters, unconstrained parameters and catch-alls.
+// Lookups only slice the request path and never allocate.
+//
+// Fields:
+//   - label: Static path text covered by this node, without leading or trailing "/"
+//   - segment: Parsed segment of the trie node this edge starts at
+//   - route: Trie node at the end of the label, whose handlers are used on a match
+//   - static: Static children keyed by the first segment of their label
+//   - params: Parameter children, constrained ones first, in registration order
+//   - catchAll: Catch-all children in registration order
+type radixNode[RouteState RouteStateCompatible] struct {
+	label    string
+	segment  routeSegment
+	route    *Route[RouteState]
+	static   map[string]*radixNode[RouteState]
+	params   []*radixNode[RouteState]
+	catchAll []*radixNode[RouteState]
+}
+
+// compileRadix builds the lookup index for a set of root-level trie nodes.
+func compileRadix[RouteState RouteStateCompatible](routes []*Route[RouteState]) *radixNode[RouteState] {
+	root := &radixNode[RouteState]{}
+	root.addChildren(routes)
+	return root
+}
+
+// compileRadixNode compiles the subtree rooted at a trie node, merging static chains.
+func compileRadixNode[RouteState RouteStateCompatible](route *Route[RouteState]) *radixNode[RouteState] {
+	node := &radixNode[RouteState]{
+		label:   route.PathComponent,
+		segment: route.segment,
+		route:   route,
+	}
+	if route.segment.kind == segmentStatic {
+		for len(node.route.Handlers) == 0 && len(node.route.Children) == 1 && node.route.Children[0].segment.kind == segmentStatic {
+			node.route = node.route.Children[0]
+			node.label += "/" + node.route.PathComponent
+		}
+	}
+	node.addChildren(node.route.Children)
+	return node
+}
+
+// addChildren compiles the given trie nodes and files them under the matching priority lists.
+func (self *radixNode[RouteState]) addChildren(routes []*Route[RouteState]) {
+	unconstrained := []*radixNode[RouteState]{}
+	for i := range routes {
+		child := compileRadixNode(routes[i])
+		switch child.segment.kind {
+		case segmentStatic:
+			if self.static == nil {
+				self.static = map[string]*radixNode[RouteState]{}
+			}
+			self.static[routes[i].PathComponent] = child
+		case segmentParam:
+			if child.segment.matcher != nil {
+				self.params = append(self.params, child)
+			} else {
+				unconstrained = append(unconstrained, child)
+			}
+		case segmentCatchAll:
+			self.catchAll = append(self.catchAll, child)
+		}
+	}
+	self.params = append(self.params, unconstrained...)
+}
+
+// lookup resolves a path using the compiled index, compiling it first if a registration
+// invalidated it. Captured parameters are appended to params, so a caller that passes a
+// slice with spare capacity gets an allocation-free lookup.
+func (self *RouteCollection[RouteState]) lookup(path string, params PathParams) (*Route[RouteState], PathParams) {
+	index := self.index.Load()
+	if index == nil {
+		index = self.compileIndex()
+	}
+	if len(path) > 0 {
+		path = path[1:]
+	}
+	if len(path) > 0 && path[len(path)-1] == '/' {
+		path = path[:len(path)-1]
+	}
+	return index.matchChildren(path, params)
+}
+
+// compileIndex compiles and publishes the lookup index unless a concurrent lookup already
+// did so. The trie nodes it references are never modified afterwards, since registrations
+// copy every node they change.
+func (self *RouteCollection[RouteState]) compileIndex() *radixNode[RouteState] {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	index := self.index.Load()
+	if index == nil {
+		index = compileRadix(self.Routes)
+		self.index.Store(index)
+	}
+	return index
+}
+
+// matchChildren matches the remaining path, which holds at least one (possibly empty)
+// segment, against this node's children in priority order, backtracking on failure.
+func (self *radixNode[RouteState]) matchChildren(rest string, params PathParams) (*Route[RouteState], PathParams) {
+	segment := rest
+	if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+		segment = rest[:slash]
+	}
+	if child, found := self.static[segment]; found && strings.HasPrefix(rest, child.label) {
+		if len(rest) == len(child.label) {
+			if route, captured := child.finish(params); route != nil {
+				return route, captured
+			}
+		} else if rest[len(child.label)] == '/' {
+			if route, captured := child.matchChildren(rest[len(child.label)+1:], params); route != nil {
+				return route, captured
+			}
+		}
+	}
+	if segment != "" {
+		for _, child := range self.params {
+			if child.segment.matcher != nil && !child.segment.matcher(segment) {
+				continue
+			}
+			captured := append(params, PathParam{Name: child.segment.name, Value: segment})
+			var route *Route[RouteState]
+			if len(rest) == len(segment) {
+				route, captured = child.finish(captured)
+			} else {
+				route, captured = child.matchChildren(rest[len(segment)+1:], captured)
+			}
+			if route != nil {
+				return route, captured
+			}
+		}
+	}
+	return self.matchCatchAll(rest, params)
+}
+
+// finish completes a match when the path ends exactly at this node: the node itself if it
+// has handlers, otherwise a catch-all child with an empty remainder.
+func (self *radixNode[RouteState]) finish(params PathParams) (*Route[RouteState], PathParams) {
+	if len(self.route.Handlers) > 0 {
+		return self.route, params
+	}
+	return self.matchCatchAll("", params)
+}
+
+// matchCatchAll returns the first catch-all child with handlers, capturing rest as its value.
+func (self *radixNode[RouteState]) matchCatchAll(rest string, params PathParams) (*Route[RouteState], PathParams) {
+	for _, child := range self.catchAll {
+		if len(child.route.Handlers) > 0 {
+			return child.route, append(params, PathParam{Name: child.segment.name, Value: rest})
+		}
+	}
+	return nil, params
+}
@@ -0,0 +1,124 @@
+package pilot
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func radixTestRoutes() *RouteCollection[struct{}] {
+	routes := NewRouteCollection[struct{}]()
+	for _, path := range []string{
+		"/",
+		"/api/v1/users",
+		"/api/v1/users/new",
+		"/api/v1/users/:id",
+		"/api/v1/users/:id/posts/{post:int}",
+		"/api/v1/users/new/:step/edit",
+		"/api/v1/orders/{id:uuid}",
+		"/api/v1/orders/{slug}",
+		"/api/v2/health",
+		"/static/*filepath",
+		"/static/robots.txt",
+		"/proxy/:service/*rest",
+		"/a/b/c/d",
+	} {
+		routes.AddRoute(Get, path, noopHandler)
+	}
+	return routes
+}
+
+func TestRadixMatchesTrie(t *testing.T) {
+	routes := radixTestRoutes()
+	for _, path := range []string{
+		"/", "/api", "/api/", "/api/v1", "/api/v1/users", "/api/v1/users/",
+		"/api/v1/users/new", "/api/v1/users/42", "/api/v1/users/42/posts/7",
+		"/api/v1/users/42/posts/x", "/api/v1/users/new/posts/7", "/api/v1/users/new/2/edit",
+		"/api/v1/orders/6f1c2f1e-9a4b-4e4d-8a34-3f5b2c1d0e9a", "/api/v1/orders/blue",
+		"/api/v2/health", "/api/v2/health/x", "/static", "/static/", "/static/robots.txt",
+		"/static/css/site.css", "/static//x", "/proxy/billing", "/proxy/billing/v1/x",
+		"/a/b/c", "/a/b/c/d", "/a/b/c/d/e", "/a//b", "//", "/missing",
+	} {
+		wantRoute, wantParams := routes.matchTrie(path)
+		gotRoute, gotParams := routes.MatchPath(path)
+		if gotRoute != wantRoute || !reflect.DeepEqual(gotParams, wantParams) {
+			t.Errorf("MatchPath(%q) = %v %v, trie = %v %v", path, gotRoute, gotParams, wantRoute, wantParams)
+		}
+	}
+}
+
+func TestRadixIndexInvalidation(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRoute(Get, "/a/b/c", noopHandler)
+	if route, _ := routes.MatchPath("/a/b"); route != nil {
+		t.Fatal("matched /a/b before it was registered")
+	}
+	routes.AddRoute(Get, "/a/b", noopHandler)
+	if route, _ := routes.MatchPath("/a/b"); route == nil {
+		t.Error("index was not rebuilt after registration")
+	}
+}
+
+func TestRadixLookupAllocations(t *testing.T) {
+	routes := radixTestRoutes()
+	params := make(PathParams, 0, 4)
+	for _, path := range []string{"/api/v1/users", "/api/v1/users/42/posts/7", "/static/css/site.css"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			routes.lookup(path, params[:0])
+		})
+		if allocs != 0 {
+			t.Errorf("lookup(%q) allocated %v times", path, allocs)
+		}
+		allocs = testing.AllocsPerRun(100, func() {
+			routes.MatchPathAppend(path, params[:0])
+		})
+		if allocs != 0 {
+			t.Errorf("MatchPathAppend(%q) allocated %v times", path, allocs)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { routes.MatchPath("/api/v1/users") }); allocs != 0 {
+		t.Errorf("MatchPath of a static path allocated %v times", allocs)
+	}
+
+	request := &HttpRequest{}
+	allocs := testing.AllocsPerRun(100, func() {
+		routes.MatchPathAppend("/api/v1/users/42/posts/7", request.paramBuffer[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("routing with the request's parameter buffer allocated %v times", allocs)
+	}
+}
+
+func benchmarkRoutes(count int) *RouteCollection[struct{}] {
+	routes := NewRouteCollection[struct{}]()
+	for i := 0; i < count; i++ {
+		routes.AddRoute(Get, fmt.Sprintf("/api/v1/resource%d", i), noopHandler)
+		routes.AddRoute(Get, fmt.Sprintf("/api/v1/resource%d/:id", i), noopHandler)
+		routes.AddRoute(Get, fmt.Sprintf("/api/v1/resource%d/:id/items/{item:int}", i), noopHandler)
+	}
+	return routes
+}
+
+func BenchmarkMatchPath(b *testing.B) {
+	routes := benchmarkRoutes(200)
+	paths := map[string]string{
+		"static": "/api/v1/resource150",
+		"param":  "/api/v1/resource150/42",
+		"nested": "/api/v1/resource150/42/items/7",
+	}
+	for name, path := range paths {
+		b.Run("radix/"+name, func(b *testing.B) {
+			params := make(PathParams, 0, 4)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				routes.MatchPathAppend(path, params[:0])
+			}
+		})
+		b.Run("trie/"+name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				routes.matchTrie(path)
+			}
+		})
+	}
+}
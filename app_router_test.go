@@ -1,6 +1,7 @@
 package pilot
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -45,3 +46,307 @@ func TestPathListFromString(t *testing.T) {
 		})
 	}
 }
+
+func noopHandler(req *RouteRequest[struct{}]) *HttpResponse {
+	return StringResponse("")
+}
+
+func TestMatchPathParams(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRoute(Get, "/users", noopHandler)
+	routes.AddRoute(Get, "/users/new", noopHandler)
+	routes.AddRoute(Get, "/users/:id", noopHandler)
+	routes.AddRoute(Get, "/users/:id/posts/:post", noopHandler)
+	routes.AddRoute(Get, "/users/new/:step/edit", noopHandler)
+
+	tests := []struct {
+		name      string
+		path      string
+		component string
+		want      PathParams
+	}{
+		{
+			name:      "static",
+			path:      "/users",
+			component: "users",
+			want:      PathParams{},
+		},
+		{
+			name:      "static wins over param",
+			path:      "/users/new",
+			component: "new",
+			want:      PathParams{},
+		},
+		{
+			name:      "param",
+			path:      "/users/42",
+			component: ":id",
+			want:      PathParams{{Name: "id", Value: "42"}},
+		},
+		{
+			name:      "multiple params",
+			path:      "/users/42/posts/7",
+			component: ":post",
+			want:      PathParams{{Name: "id", Value: "42"}, {Name: "post", Value: "7"}},
+		},
+		{
+			name:      "backtracks from static branch",
+			path:      "/users/new/posts/7",
+			component: ":post",
+			want:      PathParams{{Name: "id", Value: "new"}, {Name: "post", Value: "7"}},
+		},
+		{
+			name:      "param below static",
+			path:      "/users/new/2/edit",
+			component: "edit",
+			want:      PathParams{{Name: "step", Value: "2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params := routes.MatchPath(tt.path)
+			if route == nil {
+				t.Fatalf("MatchPath(%q) = nil", tt.path)
+			}
+			if route.PathComponent != tt.component {
+				t.Errorf("MatchPath(%q) matched %q, want %q", tt.path, route.PathComponent, tt.component)
+			}
+			if !reflect.DeepEqual(params, tt.want) {
+				t.Errorf("MatchPath(%q) params = %v, want %v", tt.path, params, tt.want)
+			}
+		})
+	}
+
+	for _, path := range []string{"/users/42/posts", "/accounts", "/users/new/2"} {
+		if route, _ := routes.MatchPath(path); route != nil {
+			t.Errorf("MatchPath(%q) = %q, want nil", path, route.PathComponent)
+		}
+	}
+}
+
+func TestGetParam(t *testing.T) {
+	req := HttpRequest{
+		Params: PathParams{
+			{Name: "id", Value: "42"},
+			{Name: "key", Value: "6f1c2f1e-9a4b-4e4d-8a34-3f5b2c1d0e9a"},
+		},
+	}
+	if req.GetParam("id") != "42" {
+		t.Error("GetParam(id) != 42")
+	}
+	if req.GetParam("missing") != "" {
+		t.Error("GetParam(missing) should be empty")
+	}
+	if v := req.GetParamInt64("id"); v == nil || *v != 42 {
+		t.Error("GetParamInt64(id) != 42")
+	}
+	if v := req.GetParamInt64("key"); v != nil {
+		t.Error("GetParamInt64(key) should be nil")
+	}
+	if v := req.GetParamUUID("key"); v == nil || v.String() != "6f1c2f1e-9a4b-4e4d-8a34-3f5b2c1d0e9a" {
+		t.Error("GetParamUUID(key) did not parse")
+	}
+	if v := req.GetParamUUID("id"); v != nil {
+		t.Error("GetParamUUID(id) should be nil")
+	}
+}
+
+func TestMatchPathCatchAll(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRoute(Get, "/static/*filepath", noopHandler)
+	routes.AddRoute(Get, "/static/robots.txt", noopHandler)
+	routes.AddRoute(Get, "/proxy/:service/*rest", noopHandler)
+	routes.AddRoute(Get, "/proxy/:service", noopHandler)
+
+	tests := []struct {
+		name      string
+		path      string
+		component string
+		want      PathParams
+	}{
+		{
+			name:      "single remainder",
+			path:      "/static/site.css",
+			component: "*filepath",
+			want:      PathParams{{Name: "filepath", Value: "site.css"}},
+		},
+		{
+			name:      "nested remainder",
+			path:      "/static/css/vendor/site.css",
+			component: "*filepath",
+			want:      PathParams{{Name: "filepath", Value: "css/vendor/site.css"}},
+		},
+		{
+			name:      "empty remainder",
+			path:      "/static/",
+			component: "*filepath",
+			want:      PathParams{{Name: "filepath", Value: ""}},
+		},
+		{
+			name:      "static wins over catch-all",
+			path:      "/static/robots.txt",
+			component: "robots.txt",
+			want:      PathParams{},
+		},
+		{
+			name:      "after param",
+			path:      "/proxy/billing/v1/invoices",
+			component: "*rest",
+			want:      PathParams{{Name: "service", Value: "billing"}, {Name: "rest", Value: "v1/invoices"}},
+		},
+		{
+			name:      "handler wins over empty remainder",
+			path:      "/proxy/billing",
+			component: ":service",
+			want:      PathParams{{Name: "service", Value: "billing"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params := routes.MatchPath(tt.path)
+			if route == nil {
+				t.Fatalf("MatchPath(%q) = nil", tt.path)
+			}
+			if route.PathComponent != tt.component {
+				t.Errorf("MatchPath(%q) matched %q, want %q", tt.path, route.PathComponent, tt.component)
+			}
+			if !reflect.DeepEqual(params, tt.want) {
+				t.Errorf("MatchPath(%q) params = %v, want %v", tt.path, params, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPathConstrainedParams(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	routes.AddRoute(Get, "/items/{id:int}", noopHandler)
+	routes.AddRoute(Get, "/items/{slug}", noopHandler)
+	routes.AddRoute(Get, "/orders/{id:uuid}", noopHandler)
+	routes.AddRoute(Get, "/tags/{tag:[a-z]+}/count", noopHandler)
+
+	tests := []struct {
+		name      string
+		path      string
+		component string
+		want      PathParams
+	}{
+		{
+			name:      "int constraint",
+			path:      "/items/42",
+			component: "{id:int}",
+			want:      PathParams{{Name: "id", Value: "42"}},
+		},
+		{
+			name:      "falls through to unconstrained sibling",
+			path:      "/items/blue-shirt",
+			component: "{slug}",
+			want:      PathParams{{Name: "slug", Value: "blue-shirt"}},
+		},
+		{
+			name:      "uuid constraint",
+			path:      "/orders/6f1c2f1e-9a4b-4e4d-8a34-3f5b2c1d0e9a",
+			component: "{id:uuid}",
+			want:      PathParams{{Name: "id", Value: "6f1c2f1e-9a4b-4e4d-8a34-3f5b2c1d0e9a"}},
+		},
+		{
+			name:      "regex constraint",
+			path:      "/tags/golang/count",
+			component: "count",
+			want:      PathParams{{Name: "tag", Value: "golang"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params := routes.MatchPath(tt.path)
+			if route == nil {
+				t.Fatalf("MatchPath(%q) = nil", tt.path)
+			}
+			if route.PathComponent != tt.component {
+				t.Errorf("MatchPath(%q) matched %q, want %q", tt.path, route.PathComponent, tt.component)
+			}
+			if !reflect.DeepEqual(params, tt.want) {
+				t.Errorf("MatchPath(%q) params = %v, want %v", tt.path, params, tt.want)
+			}
+		})
+	}
+
+	for _, path := range []string{"/orders/42", "/tags/Go1/count", "/tags/golang1/count"} {
+		if route, _ := routes.MatchPath(path); route != nil {
+			t.Errorf("MatchPath(%q) = %q, want nil", path, route.PathComponent)
+		}
+	}
+}
+
+func TestAddRouteConflicts(t *testing.T) {
+	routes := NewRouteCollection[struct{}]()
+	if err := routes.AddRoute(Get, "/users/:id", noopHandler); err != nil {
+		t.Fatal(err)
+	}
+	if err := routes.AddRouteHandler(Get, "/users", RouteHandler[struct{}]{Name: "users", Handler: noopHandler}); err != nil {
+		t.Fatal(err)
+	}
+	if err := routes.AddRoute(Delete, "/users/:id", noopHandler); err != nil {
+		t.Errorf("different method on same path rejected: %v", err)
+	}
+	if err := routes.AddRoute(Get, "/users/{id:int}/posts", noopHandler); err != nil {
+		t.Errorf("constrained sibling rejected: %v", err)
+	}
+
+	conflicts := []struct {
+		name    string
+		method  HttpMethod
+		path    string
+		handler RouteHandler[struct{}]
+		want    error
+	}{
+		{name: "duplicate", method: Get, path: "/users/:id", want: ErrRouteConflict},
+		{name: "ambiguous param", method: Post, path: "/users/{key}", want: ErrRouteConflict},
+		{name: "reused name", method: Get, path: "/accounts", handler: RouteHandler[struct{}]{Name: "users"}, want: ErrRouteConflict},
+		{name: "catch-all not last", method: Get, path: "/files/*path/raw", want: ErrInvalidRoute},
+		{name: "bad regex", method: Get, path: "/tags/{tag:[a-z}", want: ErrInvalidRoute},
+	}
+	for _, tt := range conflicts {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handler.Handler = noopHandler
+			if err := routes.AddRouteHandler(tt.method, tt.path, tt.handler); !errors.Is(err, tt.want) {
+				t.Errorf("AddRouteHandler(%s %s) = %v, want %v", tt.method, tt.path, err, tt.want)
+			}
+		})
+	}
+	if route := routes.FindPath("/files/x/raw", false); route != nil {
+		t.Error("rejected route was registered")
+	}
+	before := len(routes.Routes)
+	if route := routes.FindPath("/x/{a:[}", true); route != nil || len(routes.Routes) != before {
+		t.Error("FindPath created nodes for an invalid pattern")
+	}
+
+	routes.Strict = true
+	defer func() {
+		if recover() == nil {
+			t.Error("Strict mode did not panic on duplicate route")
+		}
+	}()
+	routes.AddRoute(Get, "/users", noopHandler)
+}
+
+func TestRouteCollectionList(t *testing.T) {
+	auth := func(req *RouteRequest[struct{}]) *HttpResponse { return nil }
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/", noopHandler)
+	app.AddRouteGroup("/users", NewRouteGroup(
+		GetRoute("/:id", noopHandler).Named("user.show"),
+		DeleteRoute("/:id", noopHandler, auth),
+		GetRoute("", noopHandler),
+	).Use(auth))
+
+	want := []RouteInfo{
+		{Method: Get, Path: "/", Middleware: 0},
+		{Method: Get, Path: "/users", Middleware: 1},
+		{Method: Delete, Path: "/users/:id", Middleware: 2},
+		{Method: Get, Path: "/users/:id", Name: "user.show", Middleware: 1},
+	}
+	if got := app.Routes.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+}
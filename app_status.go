@@ -1,23 +1,210 @@
 package pilot
 
+import (
+	"strconv"
+	"strings"
+)
+
+// StatusCode is the three-digit status code of an HTTP response.
 type StatusCode int
 
+// Status code constants for every code in the IANA HTTP Status Code Registry
+// (https://www.iana.org/assignments/http-status-codes), named after their reason phrases
+// as defined by RFC 9110 and the RFCs that registered the others. Codes outside the
+// registry can be given a reason phrase with RegisterStatusCode.
 const (
-	StatusOK                  StatusCode = 200
-	StatusNoContent           StatusCode = 204
-	StatusBadRequest          StatusCode = 400
-	StatusUnauthorized        StatusCode = 401
-	StatusForbidden           StatusCode = 403
-	StatusNotFound            StatusCode = 404
-	StatusInternalServerError StatusCode = 500
+	StatusContinue           StatusCode = 100
+	StatusSwitchingProtocols StatusCode = 101
+	StatusProcessing         StatusCode = 102
+	StatusEarlyHints         StatusCode = 103
+
+	StatusOK                          StatusCode = 200
+	StatusCreated                     StatusCode = 201
+	StatusAccepted                    StatusCode = 202
+	StatusNonAuthoritativeInformation StatusCode = 203
+	StatusNoContent                   StatusCode = 204
+	StatusResetContent                StatusCode = 205
+	StatusPartialContent              StatusCode = 206
+	StatusMultiStatus                 StatusCode = 207
+	StatusAlreadyReported             StatusCode = 208
+	StatusIMUsed                      StatusCode = 226
+
+	StatusMultipleChoices   StatusCode = 300
+	StatusMovedPermanently  StatusCode = 301
+	StatusFound             StatusCode = 302
+	StatusSeeOther          StatusCode = 303
+	StatusNotModified       StatusCode = 304
+	StatusUseProxy          StatusCode = 305
+	StatusTemporaryRedirect StatusCode = 307
+	StatusPermanentRedirect StatusCode = 308
+
+	StatusBadRequest                  StatusCode = 400
+	StatusUnauthorized                StatusCode = 401
+	StatusPaymentRequired             StatusCode = 402
+	StatusForbidden                   StatusCode = 403
+	StatusNotFound                    StatusCode = 404
+	StatusMethodNotAllowed            StatusCode = 405
+	StatusNotAcceptable               StatusCode = 406
+	StatusProxyAuthenticationRequired StatusCode = 407
+	StatusRequestTimeout              StatusCode = 408
+	StatusConflict                    StatusCode = 409
+	StatusGone                        StatusCode = 410
+	StatusLengthRequired              StatusCode = 411
+	StatusPreconditionFailed          StatusCode = 412
+	StatusContentTooLarge             StatusCode = 413
+	StatusURITooLong                  StatusCode = 414
+	StatusUnsupportedMediaType        StatusCode = 415
+	StatusRangeNotSatisfiable         StatusCode = 416
+	StatusExpectationFailed           StatusCode = 417
+	StatusMisdirectedRequest          StatusCode = 421
+	StatusUnprocessableContent        StatusCode = 422
+	StatusLocked                      StatusCode = 423
+	StatusFailedDependency            StatusCode = 424
+	StatusTooEarly                    StatusCode = 425
+	StatusUpgradeRequired             StatusCode = 426
+	StatusPreconditionRequired        StatusCode = 428
+	StatusTooManyRequests             StatusCode = 429
+	StatusRequestHeaderFieldsTooLarge StatusCode = 431
+	StatusUnavailableForLegalReasons  StatusCode = 451
+
+	StatusInternalServerError           StatusCode = 500
+	StatusNotImplemented                StatusCode = 501
+	StatusBadGateway                    StatusCode = 502
+	StatusServiceUnavailable            StatusCode = 503
+	StatusGatewayTimeout                StatusCode = 504
+	StatusHTTPVersionNotSupported       StatusCode = 505
+	StatusVariantAlsoNegotiates         StatusCode = 506
+	StatusInsufficientStorage           StatusCode = 507
+	StatusLoopDetected                  StatusCode = 508
+	StatusNotExtended                   StatusCode = 510
+	StatusNetworkAuthenticationRequired StatusCode = 511
 )
 
+// StatusCodeDescriptions maps status codes to the reason phrases sent in the status line.
+// Use RegisterStatusCode to add or change an entry, and StatusCode.Reason to look one up.
 var StatusCodeDescriptions = map[StatusCode]string{
-	StatusOK:                  "OK",
-	StatusNoContent:           "No Content",
-	StatusBadRequest:          "Bad Request",
-	StatusNotFound:            "Not Found",
-	StatusUnauthorized:        "Unauthorized",
-	StatusForbidden:           "Forbidden",
-	StatusInternalServerError: "Internal Server Error",
+	StatusContinue:           "Continue",
+	StatusSwitchingProtocols: "Switching Protocols",
+	StatusProcessing:         "Processing",
+	StatusEarlyHints:         "Early Hints",
+
+	StatusOK:                          "OK",
+	StatusCreated:                     "Created",
+	StatusAccepted:                    "Accepted",
+	StatusNonAuthoritativeInformation: "Non-Authoritative Information",
+	StatusNoContent:                   "No Content",
+	StatusResetContent:                "Reset Content",
+	StatusPartialContent:              "Partial Content",
+	StatusMultiStatus:                 "Multi-Status",
+	StatusAlreadyReported:             "Already Reported",
+	StatusIMUsed:                      "IM Used",
+
+	StatusMultipleChoices:   "Multiple Choices",
+	StatusMovedPermanently:  "Moved Permanently",
+	StatusFound:             "Found",
+	StatusSeeOther:          "See Other",
+	StatusNotModified:       "Not Modified",
+	StatusUseProxy:          "Use Proxy",
+	StatusTemporaryRedirect: "Temporary Redirect",
+	StatusPermanentRedirect: "Permanent Redirect",
+
+	StatusBadRequest:                  "Bad Request",
+	StatusUnauthorized:                "Unauthorized",
+	StatusPaymentRequired:             "Payment Required",
+	StatusForbidden:                   "Forbidden",
+	StatusNotFound:                    "Not Found",
+	StatusMethodNotAllowed:            "Method Not Allowed",
+	StatusNotAcceptable:               "Not Acceptable",
+	StatusProxyAuthenticationRequired: "Proxy Authentication Required",
+	StatusRequestTimeout:              "Request Timeout",
+	StatusConflict:                    "Conflict",
+	StatusGone:                        "Gone",
+	StatusLengthRequired:              "Length Required",
+	StatusPreconditionFailed:          "Precondition Failed",
+	StatusContentTooLarge:             "Content Too Large",
+	StatusURITooLong:                  "URI Too Long",
+	StatusUnsupportedMediaType:        "Unsupported Media Type",
+	StatusRangeNotSatisfiable:         "Range Not Satisfiable",
+	StatusExpectationFailed:           "Expectation Failed",
+	StatusMisdirectedRequest:          "Misdirected Request",
+	StatusUnprocessableContent:        "Unprocessable Content",
+	StatusLocked:                      "Locked",
+	StatusFailedDependency:            "Failed Dependency",
+	StatusTooEarly:                    "Too Early",
+	StatusUpgradeRequired:             "Upgrade Required",
+	StatusPreconditionRequired:        "Precondition Required",
+	StatusTooManyRequests:             "Too Many Requests",
+	StatusRequestHeaderFieldsTooLarge: "Request Header Fields Too Large",
+	StatusUnavailableForLegalReasons:  "Unavailable For Legal Reasons",
+
+	StatusInternalServerError:           "Internal Server Error",
+	StatusNotImplemented:                "Not Implemented",
+	StatusBadGateway:                    "Bad Gateway",
+	StatusServiceUnavailable:            "Service Unavailable",
+	StatusGatewayTimeout:                "Gateway Timeout",
+	StatusHTTPVersionNotSupported:       "HTTP Version Not Supported",
+	StatusVariantAlsoNegotiates:         "Variant Also Negotiates",
+	StatusInsufficientStorage:           "Insufficient Storage",
+	StatusLoopDetected:                  "Loop Detected",
+	StatusNotExtended:                   "Not Extended",
+	StatusNetworkAuthenticationRequired: "Network Authentication Required",
+}
+
+// RegisterStatusCode sets the reason phrase sent in the status line for a status code,
+// either to give an unregistered code a phrase or to replace the phrase of a standard one.
+// Clients must not depend on reason phrases, so this only changes what is displayed in
+// logs and debugging tools. It panics if code is not a three-digit number or reason
+// contains control characters.
+//
+// Parameters:
+//   - code: The status code, from 100 to 999
+//   - reason: The reason phrase, such as "Too Many Requests"
+//
+// Returns:
+//   - StatusCode: The status code, to declare a constant-like variable with
+//
+// Example:
+//
+//	var StatusTeapot = pilot.RegisterStatusCode(418, "I'm a teapot")
+//
+//	response := pilot.StringResponse("short and stout")
+//	response.SetStatus(StatusTeapot)
+func RegisterStatusCode(code int, reason string) StatusCode {
+	if code < 100 || code > 999 {
+		panic("pilot: invalid HTTP status code " + strconv.Itoa(code))
+	}
+	if strings.ContainsFunc(reason, func(r rune) bool { return (r < ' ' && r != '\t') || r == 0x7f }) {
+		panic("pilot: invalid reason phrase " + strconv.Quote(reason))
+	}
+	status := StatusCode(code)
+	StatusCodeDescriptions[status] = reason
+	return status
+}
+
+// Reason returns the reason phrase for the status code: the registered one, or for codes
+// without one, a generic phrase for its class such as "Client Error".
+func (self StatusCode) Reason() string {
+	if reason, found := StatusCodeDescriptions[self]; found {
+		return reason
+	}
+	switch self / 100 {
+	case 1:
+		return "Informational"
+	case 2:
+		return "Success"
+	case 3:
+		return "Redirection"
+	case 4:
+		return "Client Error"
+	case 5:
+		return "Server Error"
+	}
+	return "Unknown"
+}
+
+// allowsBody reports whether a response with the status code can have content. Responses
+// with a 1xx, 204 or 304 status end after their headers (RFC 9110, section 6.4.1), so they
+// are sent without a body or Content-Length.
+func (self StatusCode) allowsBody() bool {
+	return self >= 200 && self != StatusNoContent && self != StatusNotModified
 }
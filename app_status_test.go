@@ -0,0 +1,50 @@
+package pilot
+
+import (
+	"testing"
+)
+
+func TestStatusCodeReason(t *testing.T) {
+	tests := map[StatusCode]string{
+		StatusCreated:                    "Created",
+		StatusUnprocessableContent:       "Unprocessable Content",
+		StatusUnavailableForLegalReasons: "Unavailable For Legal Reasons",
+		StatusCode(299):                  "Success",
+		StatusCode(499):                  "Client Error",
+		StatusCode(999):                  "Unknown",
+	}
+	for status, want := range tests {
+		if got := status.Reason(); got != want {
+			t.Errorf("%d.Reason() = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestRegisterStatusCode(t *testing.T) {
+	t.Cleanup(func() {
+		delete(StatusCodeDescriptions, 599)
+		StatusCodeDescriptions[StatusTooManyRequests] = "Too Many Requests"
+	})
+	custom := RegisterStatusCode(599, "Network Connect Timeout Error")
+	if custom != 599 || custom.Reason() != "Network Connect Timeout Error" {
+		t.Errorf("custom status = %d %q", custom, custom.Reason())
+	}
+	RegisterStatusCode(429, "Slow Down")
+	if reason := StatusTooManyRequests.Reason(); reason != "Slow Down" {
+		t.Errorf("replaced reason = %q", reason)
+	}
+
+	for _, tt := range []struct {
+		code   int
+		reason string
+	}{{99, "Too Small"}, {1000, "Too Large"}, {299, "Split\r\nHeader: injected"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterStatusCode(%d, %q) did not panic", tt.code, tt.reason)
+				}
+			}()
+			RegisterStatusCode(tt.code, tt.reason)
+		}()
+	}
+}
@@ -0,0 +1,186 @@
+package pilot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func newTestApplication() *Application[struct{}] {
+	app := NewInlineApplication[struct{}]("0", nil, context.Background())
+	app.SilentMode = true
+	return app
+}
+
+func serveTestRequest(app *Application[struct{}], method HttpMethod, path string) *HttpResponse {
+	request := &HttpRequest{
+		Method:  method,
+		Path:    path,
+		Headers: Header{},
+	}
+	return app.serveRequest(context.Background(), request, func(string) {})
+}
+
+func TestServeRequestMethodNotAllowed(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/users/:id", noopHandler)
+	app.Routes.AddRoute(Delete, "/users/:id", noopHandler)
+
+	response := serveTestRequest(app, Post, "/users/42")
+	if response.StatusCode != StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", response.StatusCode, StatusMethodNotAllowed)
+	}
+	if allow := response.Headers.Get("Allow"); allow != "DELETE, GET, HEAD, OPTIONS" {
+		t.Errorf("Allow = %q, want %q", allow, "DELETE, GET, HEAD, OPTIONS")
+	}
+
+	response = serveTestRequest(app, Post, "/accounts")
+	if response.StatusCode != StatusNotFound {
+		t.Errorf("status = %d, want %d", response.StatusCode, StatusNotFound)
+	}
+}
+
+func TestServeRequestOptions(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/users", noopHandler)
+	app.Routes.AddRoute(Post, "/users", noopHandler)
+
+	response := serveTestRequest(app, Options, "/users")
+	if response.StatusCode != StatusOK {
+		t.Fatalf("status = %d, want %d", response.StatusCode, StatusOK)
+	}
+	if allow := response.Headers.Get("Allow"); allow != "GET, HEAD, OPTIONS, POST" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, HEAD, OPTIONS, POST")
+	}
+	if methods := response.Headers.Get("Access-Control-Allow-Methods"); methods != "GET, HEAD, OPTIONS, POST" {
+		t.Errorf("Access-Control-Allow-Methods = %q, want %q", methods, "GET, HEAD, OPTIONS, POST")
+	}
+	if origin := response.Headers.Get("Access-Control-Allow-Origin"); origin != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want %q", origin, "*")
+	}
+}
+
+func writeTestResponse(t *testing.T, response *HttpResponse) string {
+	t.Helper()
+	server, client := net.Pipe()
+	go func() {
+		response.Write(server)
+		server.Close()
+	}()
+	raw, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
+
+func TestServeRequestHead(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/health", func(req *RouteRequest[struct{}]) *HttpResponse {
+		response := StringResponse("OK")
+		response.SetHeader("X-Handler", "get")
+		return response
+	})
+	app.Routes.AddRoute(Get, "/status", noopHandler)
+	app.Routes.AddRoute(Head, "/status", func(req *RouteRequest[struct{}]) *HttpResponse {
+		response := StringResponse("")
+		response.SetHeader("X-Handler", "head")
+		return response
+	})
+
+	response := serveTestRequest(app, Head, "/health")
+	if response.StatusCode != StatusOK || response.Headers.Get("X-Handler") != "get" {
+		t.Fatalf("HEAD /health = %d %q, want GET handler", response.StatusCode, response.Headers.Get("X-Handler"))
+	}
+	response.omitBody = true
+	raw := writeTestResponse(t, response)
+	if !strings.Contains(raw, "Content-Length: 2\r\n") {
+		t.Errorf("HEAD response missing Content-Length: %q", raw)
+	}
+	if !strings.HasSuffix(raw, "\r\n\r\n") {
+		t.Errorf("HEAD response contains a body: %q", raw)
+	}
+
+	response = serveTestRequest(app, Head, "/status")
+	if response.Headers.Get("X-Handler") != "head" {
+		t.Errorf("HEAD /status used %q handler, want explicit HEAD handler", response.Headers.Get("X-Handler"))
+	}
+}
+
+func TestErrorHandlers(t *testing.T) {
+	app := newTestApplication()
+	app.Routes.AddRoute(Get, "/users/:id", noopHandler)
+	tagged := func(req *RouteRequest[struct{}]) *HttpResponse {
+		req.Request.Headers.Set("X-Middleware", "ran")
+		return nil
+	}
+	envelope := func(status StatusCode) RouteHandlerFn[struct{}] {
+		return func(req *RouteRequest[struct{}]) *HttpResponse {
+			response := JsonResponse(map[string]string{
+				"error":      string(req.Request.Method) + " " + req.Request.Path,
+				"middleware": req.Request.Headers.Get("X-Middleware"),
+			})
+			response.SetStatus(status)
+			return response
+		}
+	}
+	app.NotFoundHandler = &RouteHandler[struct{}]{Handler: envelope(StatusNotFound), Middleware: []MiddlewareFn[struct{}]{tagged}}
+	app.MethodNotAllowedHandler = &RouteHandler[struct{}]{Handler: envelope(StatusMethodNotAllowed)}
+	app.BadRequestHandler = &RouteHandler[struct{}]{Handler: envelope(StatusBadRequest)}
+
+	response := serveTestRequest(app, Get, "/missing")
+	if response.StatusCode != StatusNotFound || string(response.Body) != `{"error":"GET /missing","middleware":"ran"}` {
+		t.Errorf("NotFoundHandler response = %d %s", response.StatusCode, response.Body)
+	}
+
+	response = serveTestRequest(app, Post, "/users/42")
+	if response.StatusCode != StatusMethodNotAllowed || response.Headers.Get("Content-Type") != "application/json" {
+		t.Errorf("MethodNotAllowedHandler response = %d %s", response.StatusCode, response.Body)
+	}
+	if allow := response.Headers.Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, HEAD, OPTIONS")
+	}
+
+	response = app.serveMalformedRequest(context.Background(), &HttpRequest{Headers: Header{}}, func(string) {})
+	if response.StatusCode != StatusBadRequest || response.Headers.Get("Content-Type") != "application/json" {
+		t.Errorf("BadRequestHandler response = %d %s", response.StatusCode, response.Body)
+	}
+}
+
+func TestExtensionMethods(t *testing.T) {
+	purge := RegisterMethod("PURGE")
+	if again := RegisterMethod("PURGE"); again != purge {
+		t.Errorf("registering PURGE twice = %q", again)
+	}
+	app := newTestApplication()
+	if err := app.Routes.AddRoute(purge, "/cache/*key", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse("purged " + req.Request.GetParam("key"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Routes.AddRoute("BREW", "/coffee", noopHandler); !errors.Is(err, ErrInvalidRoute) {
+		t.Errorf("AddRoute with an unregistered method = %v, want ErrInvalidRoute", err)
+	}
+
+	if response := serveTestRequest(app, purge, "/cache/users/1"); string(response.Body) != "purged users/1" {
+		t.Errorf("PURGE /cache/users/1 = %d %q", response.StatusCode, response.Body)
+	}
+	for _, method := range []HttpMethod{"TRACE", "PROPFIND", None} {
+		if response := serveTestRequest(app, method, "/cache/users/1"); response.StatusCode != StatusNotImplemented {
+			t.Errorf("%s = %d, want %d", method, response.StatusCode, StatusNotImplemented)
+		}
+	}
+	if response := serveTestRequest(app, Get, "/cache/users/1"); response.StatusCode != StatusMethodNotAllowed || response.Headers.Get("Allow") != "OPTIONS, PURGE" {
+		t.Errorf("GET = %d Allow %q", response.StatusCode, response.Headers.Get("Allow"))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterMethod accepted an invalid token")
+		}
+	}()
+	RegisterMethod("BAD METHOD")
+}
@@ -0,0 +1,68 @@
+package pilot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// Default timeouts of a new Application. See the Application fields of the same names.
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultReadBodyTimeout   = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 60 * time.Second
+)
+
+// deadline returns the deadline for an operation that may take timeout, starting now,
+// or the zero time, which means no deadline, if timeout is zero or less.
+func deadline(timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeout)
+}
+
+// isTimeout reports whether err is the result of a connection deadline expiring.
+func isTimeout(err error) bool {
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// serveTimeout builds the 408 response for a request that was not received in time.
+func (a *Application[RouteState]) serveTimeout() *HttpResponse {
+	response := StringResponse("408 request timeout")
+	response.SetStatus(StatusRequestTimeout)
+	response.ApplyCors(&a.CorsOrigin, &a.CorsHeaders, &a.CorsMethods)
+	return response
+}
+
+// runWithTimeout runs a handler chain, giving up after HandlerTimeout. The chain receives a
+// context that is cancelled when the time is up; if it has not returned by then, the client
+// gets a 503 Service Unavailable response while the chain keeps running in the background
+// until it notices the cancellation, and whatever it returns is discarded and released.
+func (a *Application[RouteState]) runWithTimeout(cn context.Context, logf func(string), run func(cn context.Context) *HttpResponse) *HttpResponse {
+	if a.HandlerTimeout <= 0 {
+		return run(cn)
+	}
+	cn, cancel := context.WithTimeout(cn, a.HandlerTimeout)
+	defer cancel()
+	done := make(chan *HttpResponse, 1)
+	go func() {
+		done <- run(cn)
+	}()
+	select {
+	case response := <-done:
+		return response
+	case <-cn.Done():
+		logf("Handler did not finish within HandlerTimeout, sending 503.")
+		go func() {
+			if late := <-done; late != nil {
+				late.releaseBody()
+			}
+		}()
+		response := StringResponse("503 service unavailable")
+		response.SetStatus(StatusServiceUnavailable)
+		return response
+	}
+}
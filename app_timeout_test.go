@@ -0,0 +1,85 @@
+package pilot
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTimeouts(t *testing.T) {
+	newApp := func() *Application[struct{}] {
+		app := newTestApplication()
+		app.ReadHeaderTimeout = 50 * time.Millisecond
+		app.ReadBodyTimeout = 50 * time.Millisecond
+		app.Routes.AddRoute(Post, "/", noopHandler)
+		return app
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "POST / HTTP/1.1\r\nHost: a\r\n")
+		if response := readTestResponse(t, reader); response.StatusCode != int(StatusRequestTimeout) || !response.Close {
+			t.Errorf("stalled headers = %d, close %v", response.StatusCode, response.Close)
+		}
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("body", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		io.WriteString(client, "POST / HTTP/1.1\r\nContent-Length: 10\r\n\r\nabc")
+		if response := readTestResponse(t, reader); response.StatusCode != int(StatusRequestTimeout) || !response.Close {
+			t.Errorf("stalled body = %d, close %v", response.StatusCode, response.Close)
+		}
+		assertClosed(t, client, reader)
+	})
+
+	t.Run("no request", func(t *testing.T) {
+		client, reader := startTestConnection(t, newApp())
+		assertClosed(t, client, reader)
+	})
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	app := newTestApplication()
+	app.HandlerTimeout = 50 * time.Millisecond
+	cancelled := make(chan struct{})
+	app.Routes.AddRoute(Get, "/slow", func(req *RouteRequest[struct{}]) *HttpResponse {
+		<-req.Context.Done()
+		close(cancelled)
+		return StringResponse("too late")
+	})
+	app.Routes.AddRoute(Get, "/fast", noopHandler)
+
+	if response := serveTestRequest(app, Get, "/slow"); response.StatusCode != StatusServiceUnavailable {
+		t.Errorf("GET /slow = %d, want 503", response.StatusCode)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("handler context was not cancelled")
+	}
+	if response := serveTestRequest(app, Get, "/fast"); response.StatusCode != StatusOK {
+		t.Errorf("GET /fast = %d", response.StatusCode)
+	}
+}
+
+func TestWriteTimeout(t *testing.T) {
+	app := newTestApplication()
+	app.WriteTimeout = 50 * time.Millisecond
+	app.Routes.AddRoute(Get, "/large", func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse(strings.Repeat("x", 1<<20))
+	})
+	client, reader := startTestConnection(t, app)
+	io.WriteString(client, "GET /large HTTP/1.1\r\n\r\n")
+	time.Sleep(200 * time.Millisecond)
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("connection was not closed after the write timeout: %v", err)
+	}
+	if len(data) >= 1<<20 {
+		t.Errorf("read %d bytes, want the response to be cut off", len(data))
+	}
+}
@@ -0,0 +1,67 @@
+package pilot
+
+import (
+	"mime"
+	"strings"
+)
+
+// requestedVersion returns the API version a request asks for, looking at VersionHeader,
+// then a VersionMediaType in the Accept header, then VersionQueryParam. If none of them
+// names a version, DefaultVersion is returned and explicit is false.
+func (a *Application[RouteState]) requestedVersion(request *HttpRequest) (version string, explicit bool) {
+	if a.VersionHeader != "" {
+		if version = strings.TrimSpace(request.Headers.Get(a.VersionHeader)); version != "" {
+			return version, true
+		}
+	}
+	if a.VersionMediaType != "" {
+		if version = mediaTypeVersion(request.Headers.joined("Accept"), a.VersionMediaType); version != "" {
+			return version, true
+		}
+	}
+	if a.VersionQueryParam != "" {
+		if value := request.QueryGetString(a.VersionQueryParam); value != nil && *value != "" {
+			return *value, true
+		}
+	}
+	return a.DefaultVersion, false
+}
+
+// mediaTypeVersion extracts the version from the first media range in an Accept header
+// that uses the vendor media type prefix, either as a ".v<version>" suffix
+// ("application/vnd.acme.v2+json") or as a version parameter
+// ("application/vnd.acme+json; version=2").
+func mediaTypeVersion(accept string, prefix string) string {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil || !strings.HasPrefix(mediaType, prefix) {
+			continue
+		}
+		if version := params["version"]; version != "" {
+			return version
+		}
+		rest, _, _ := strings.Cut(mediaType[len(prefix):], "+")
+		if version, found := strings.CutPrefix(rest, ".v"); found && version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// varyByVersion adds the request headers the version was selected by to the response's
+// Vary header, so caches keep the responses of different versions apart.
+func (a *Application[RouteState]) varyByVersion(response *HttpResponse) {
+	vary := []string{}
+	if existing := response.Headers.joined("Vary"); existing != "" {
+		vary = append(vary, existing)
+	}
+	if a.VersionHeader != "" {
+		vary = append(vary, a.VersionHeader)
+	}
+	if a.VersionMediaType != "" {
+		vary = append(vary, "Accept")
+	}
+	if len(vary) > 0 {
+		response.SetHeader("Vary", strings.Join(vary, ", "))
+	}
+}
@@ -0,0 +1,108 @@
+package pilot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func versionHandler(name string) RouteHandlerFn[struct{}] {
+	return func(req *RouteRequest[struct{}]) *HttpResponse {
+		return StringResponse(name + ":" + req.Version)
+	}
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	app := newTestApplication()
+	app.VersionMediaType = "application/vnd.acme"
+	app.VersionQueryParam = "api-version"
+	app.Routes.AddRouteHandler(Get, "/users", RouteHandler[struct{}]{
+		Handler:  versionHandler("v1"),
+		Version:  "1",
+		Metadata: RouteMetadata{Deprecated: time.Unix(1700000000, 0)},
+	})
+	app.Routes.AddRouteHandler(Get, "/users", RouteHandler[struct{}]{Handler: versionHandler("v2"), Version: "2"})
+	app.Routes.AddRouteHandler(Get, "/posts", RouteHandler[struct{}]{Handler: versionHandler("v2"), Version: "2"})
+	app.Routes.AddRoute(Get, "/posts", versionHandler("unversioned"))
+	if err := app.Routes.AddRouteHandler(Get, "/users", RouteHandler[struct{}]{Handler: noopHandler, Version: "2"}); !errors.Is(err, ErrRouteConflict) {
+		t.Errorf("duplicate version = %v, want ErrRouteConflict", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		query   string
+		headers map[string]string
+		status  StatusCode
+		body    string
+	}{
+		{"no version", "/users", "", nil, StatusOK, "v1:1"},
+		{"header", "/users", "", map[string]string{"Accept-Version": "2"}, StatusOK, "v2:2"},
+		{"media type", "/users", "", map[string]string{"Accept": "application/vnd.acme.v2+json"}, StatusOK, "v2:2"},
+		{"media type parameter", "/users", "", map[string]string{"Accept": "text/html, application/vnd.acme+json; version=2"}, StatusOK, "v2:2"},
+		{"query", "/users", "api-version=2", nil, StatusOK, "v2:2"},
+		{"header first", "/users", "api-version=2", map[string]string{"Accept-Version": "1"}, StatusOK, "v1:1"},
+		{"unknown", "/users", "", map[string]string{"Accept-Version": "3"}, StatusNotAcceptable, "406 version not acceptable"},
+		{"unversioned fallback", "/posts", "", map[string]string{"Accept-Version": "3"}, StatusOK, "unversioned:"},
+		{"unversioned primary", "/posts", "", nil, StatusOK, "unversioned:"},
+		{"versioned alongside unversioned", "/posts", "", map[string]string{"Accept-Version": "2"}, StatusOK, "v2:2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := Header{}
+			for key, value := range tt.headers {
+				headers.Set(key, value)
+			}
+			request := &HttpRequest{Method: Get, Path: tt.path, QueryString: tt.query, Headers: headers}
+			response := app.serveRequest(context.Background(), request, func(string) {})
+			if response.StatusCode != tt.status || string(response.Body) != tt.body {
+				t.Errorf("response = %d %q, want %d %q", response.StatusCode, response.Body, tt.status, tt.body)
+			}
+			if response.Headers.Get("Vary") != "Accept-Version, Accept" {
+				t.Errorf("Vary = %q", response.Headers.Get("Vary"))
+			}
+		})
+	}
+
+	if response := serveTestRequest(app, Get, "/users"); response.Headers.Get("Deprecation") != "@1700000000" {
+		t.Errorf("version 1 Deprecation = %q", response.Headers.Get("Deprecation"))
+	}
+	app.DefaultVersion = "2"
+	if response := serveTestRequest(app, Head, "/users"); string(response.Body) != "v2:2" || response.Headers.Get("Deprecation") != "" {
+		t.Errorf("HEAD with default version = %q %v", response.Body, response.Headers)
+	}
+
+	routes := app.Routes.List()
+	if len(routes) != 4 || routes[2].Version != "1" || routes[3].Version != "2" {
+		t.Errorf("List() = %+v", routes)
+	}
+	app.Routes.RemoveRoute(Get, "/users")
+	if route, _ := app.Routes.MatchPath("/users"); route != nil {
+		t.Error("RemoveRoute left versions of the route registered")
+	}
+}
+
+func TestVersionedRouteGroup(t *testing.T) {
+	app := newTestApplication()
+	users := NewRouteGroup(
+		GetRoute("/users", versionHandler("v1")).WithVersion("1"),
+		GetRoute("/users", versionHandler("v2")).WithVersion("2"),
+	)
+	if err := app.Routes.AddRouteGroup("/api", users); err != nil {
+		t.Fatal(err)
+	}
+	for version, want := range map[string]string{"1": "v1:1", "2": "v2:2"} {
+		request := &HttpRequest{Method: Get, Path: "/api/users", Headers: Header{"Accept-Version": {version}}}
+		if response := app.serveRequest(context.Background(), request, func(string) {}); string(response.Body) != want {
+			t.Errorf("version %s = %q, want %q", version, response.Body, want)
+		}
+	}
+
+	if err := app.Routes.RemoveRouteGroup("/api", users); err != nil {
+		t.Fatalf("RemoveRouteGroup = %v", err)
+	}
+	if route, _ := app.Routes.MatchPath("/api/users"); route != nil {
+		t.Error("RemoveRouteGroup left versions of the route registered")
+	}
+}